@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
@@ -22,12 +24,39 @@ func init() {
 	fl.String("config", "", "path to config file")
 	fl.String("log-level", "info", "log level")
 	fl.Int("http-port", 8080, "http port")
+	fl.Int("grpc-port", 0, "gRPC port of the relay API, the gRPC API is disabled when 0")
 	fl.String("bdn.ws-url", "ws://localhost:28333/ws", "BDN WebSocket URL")
 	fl.String("bdn.grpc-url", "", "BDN gRPC URL")
+	fl.Bool("bdn.grpc-insecure", false, "connect to the BDN gRPC URL without TLS")
 	fl.String("bdn.auth-header", "", "BDN auth header")
 	fl.String("dapp-private-key", "", "DApp private key")
 	fl.String("solver-private-key", "", "Solver private key")
 	fl.String("dapp-address", "", "DApp address")
+	fl.String("dapp-signer.keystore", "", "path of the encrypted keystore file of the DApp key, instead of the DApp private key")
+	fl.String("dapp-signer.password-file", "", "path of the file holding the password of the DApp keystore")
+	fl.String("dapp-signer.remote-url", "", "URL of the remote signer of the DApp, instead of the DApp private key")
+	fl.String("dapp-signer.account", "", "DApp account of the remote signer")
+	fl.String("solver-signer.keystore", "", "path of the encrypted keystore file of the Solver key, instead of the Solver private key")
+	fl.String("solver-signer.password-file", "", "path of the file holding the password of the Solver keystore")
+	fl.String("solver-signer.remote-url", "", "URL of the remote signer of the Solver, instead of the Solver private key")
+	fl.String("solver-signer.account", "", "Solver account of the remote signer")
+	fl.Uint64("atlas.chain-id", 137, "Atlas chain id, selects the EIP-712 domain of the Atlas verification contract")
+	fl.String("atlas.eth-rpc-url", "", "RPC URL of a node of the Atlas chain, used to drop expired and simulate solver operations")
+	fl.String("atlas.simulation-mode", "", "simulate solver operations of the dApp intents: empty to disable, mark or filter")
+	fl.String("store.type", "memory", "store of the intents submitted through the relay and their solutions: memory or leveldb")
+	fl.String("store.path", "", "directory of the leveldb store")
+	fl.Duration("store.retention", 24*time.Hour, "how long the intents submitted through the relay are kept")
+	fl.Int("auction.duration-ms", 0, "auction window of the DApp intents in milliseconds, solutions are not auctioned when 0")
+	fl.String("auction.min-bid", "", "minimum bid amount in wei of the solver operations of the DApp auctions")
+	fl.Int("auction.max-solvers", 0, "maximum number of solver operations of the DApp auction results, unlimited when 0")
+	fl.StringSlice("trusted-proxies", nil, "addresses and CIDR ranges of the proxies whose X-Forwarded-For header is logged as the client address")
+	fl.StringSlice("allowed-origins", nil, "browser origins allowed to open the WebSocket APIs besides the relay host, * allows any origin")
+	fl.Int("subscriptions.buffer-size", config.DefaultSubscriptionBufferSize, "number of notifications buffered per solver subscription")
+	fl.String("subscriptions.slow-consumer-policy", string(config.SlowConsumerPolicyDropNewest), "policy once the buffer of a subscription is full: drop-newest, drop-oldest or disconnect")
+	fl.Duration("subscriptions.status-interval", 30*time.Second, "period of the status notifications of the solver subscriptions, disabled when 0")
+	fl.Duration("subscriptions.max-lag", 0, "disconnect the clients whose subscription buffer overflowed and was not drained within it, disabled when 0")
+	fl.Int("subscriptions.replay-buffer-size", 1000, "number of recent intent notifications replayed to the subscriptions resuming from a sequence number, disabled when 0")
+	fl.String("auth.jwt-secret", "", "HMAC secret of the JWTs accepted by the relay API, API keys are set in the config file")
 
 	err := viper.BindPFlags(fl)
 	if err != nil {
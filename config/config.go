@@ -3,9 +3,15 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"math/big"
+	"net/netip"
 	"path"
+	"regexp"
 	"strings"
+	"time"
 
+	atlasconfig "github.com/FastLane-Labs/atlas-sdk-go/config"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -13,27 +19,383 @@ import (
 var (
 	ErrBDNURLRequired        = fmt.Errorf("either BDN WS or BDN gRPC URL is required")
 	ErrBDNAuthHeaderRequired = fmt.Errorf("BDN auth header is required")
-	ErrPrivateKeyRequired    = fmt.Errorf("either dApp or solver private key is required")
-	ErrDAppAddressRequired   = fmt.Errorf("dApp address is required when solver private key is provided")
+	ErrPrivateKeyRequired    = fmt.Errorf("either dApp or solver private key or signer is required")
+	ErrDAppAddressRequired   = fmt.Errorf("dApp address is required to subscribe a solver to intents")
+	ErrInvalidDAppAddress    = fmt.Errorf("solver dApp addresses must be hex addresses")
+	ErrUnsupportedChainID    = fmt.Errorf("unsupported Atlas chain id")
+	ErrInvalidSimulationMode = fmt.Errorf("simulation mode must be empty, mark or filter")
+	ErrEthRPCURLRequired     = fmt.Errorf("Atlas chain RPC URL is required to simulate solver operations")
+	ErrInvalidAPIKey         = fmt.Errorf("API keys require a name and a key")
+	ErrInvalidPermission     = fmt.Errorf("permission must be dapp, solver or admin")
+	ErrInvalidRateLimit      = fmt.Errorf("rate limits require a positive rate and burst")
+	ErrInvalidDAppName       = fmt.Errorf("dApp names must be unique and made of letters, digits, - and _")
+	ErrDAppKeyRequired       = fmt.Errorf("dApp private key or signer is required")
+	ErrInvalidSolverName     = fmt.Errorf("solver names must be unique and made of letters, digits, - and _")
+	ErrSolverKeyRequired     = fmt.Errorf("solver private key or signer is required")
+	ErrUnknownSolver         = fmt.Errorf("API key is bound to an unknown solver")
+	ErrUnknownDApp           = fmt.Errorf("API key is bound to an unknown dApp")
+	ErrMultipleKeySources    = fmt.Errorf("only one of private key, keystore and remote signer can be set")
+	ErrSignerAccountRequired = fmt.Errorf("remote signer requires a valid account address")
+	ErrInvalidStoreType      = fmt.Errorf("store type must be memory or leveldb")
+	ErrStorePathRequired     = fmt.Errorf("store path is required by the leveldb store")
+	ErrInvalidTrustedProxy   = fmt.Errorf("trusted proxies must be IP addresses or CIDR ranges")
+
+	ErrInvalidSlowConsumerPolicy = fmt.Errorf("slow consumer policy must be empty, drop-newest, drop-oldest or disconnect")
+	ErrInvalidSubscriptions      = fmt.Errorf("subscription buffer size, replay buffer size, status interval and max lag must not be negative")
+
+	ErrInvalidAuctionDuration   = fmt.Errorf("auction duration must be between 0 and %d ms", MaxAuctionDuration.Milliseconds())
+	ErrInvalidAuctionMinBid     = fmt.Errorf("auction minimum bid must be a non-negative decimal amount in wei")
+	ErrInvalidAuctionMaxSolvers = fmt.Errorf("auction maximum solver count must not be negative")
 )
 
 const (
 	envPrefix = "BDN_OPS_RELAY"
+
+	// DefaultDAppName is the name of the dApp of the top level dapp-private-key or dapp-signer and dapp-address
+	DefaultDAppName = "default"
+	// DefaultSolverName is the name of the solver of the top level solver-private-key or solver-signer
+	DefaultSolverName = "default"
 )
 
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 type Config struct {
-	LogLevel         string    `mapstructure:"log-level"`
-	HTTPPort         int       `mapstructure:"http-port"`
-	BDN              BDNConfig `mapstructure:"bdn"`
-	DAppPrivateKey   string    `mapstructure:"dapp-private-key"`
-	SolverPrivateKey string    `mapstructure:"solver-private-key"`
-	DAppAddress      string    `mapstructure:"dapp-address"`
+	LogLevel         string          `mapstructure:"log-level"`
+	HTTPPort         int             `mapstructure:"http-port"`
+	GRPCPort         int             `mapstructure:"grpc-port"`
+	BDN              BDNConfig       `mapstructure:"bdn"`
+	DAppPrivateKey   string          `mapstructure:"dapp-private-key"`
+	SolverPrivateKey string          `mapstructure:"solver-private-key"`
+	DAppAddress      string          `mapstructure:"dapp-address"`
+	DAppSigner       SignerConfig    `mapstructure:"dapp-signer"`
+	SolverSigner     SignerConfig    `mapstructure:"solver-signer"`
+	Atlas            AtlasConfig     `mapstructure:"atlas"`
+	Auth             AuthConfig      `mapstructure:"auth"`
+	RateLimit        RateLimitConfig `mapstructure:"rate-limit"`
+	Store            StoreConfig     `mapstructure:"store"`
+	// Subscriptions configures the notification buffers of the solver subscriptions
+	Subscriptions SubscriptionsConfig `mapstructure:"subscriptions"`
+	// TrustedProxies are the addresses and CIDR ranges of the proxies whose X-Forwarded-For header is honoured in the logs
+	TrustedProxies []string `mapstructure:"trusted-proxies"`
+	// AllowedOrigins are the browser origins allowed to open the WebSocket APIs besides the relay host, "*" allows any origin
+	AllowedOrigins []string `mapstructure:"allowed-origins"`
+	// Auction is the auction of the dApp of DAppPrivateKey, the dApps of the dapps list set their own
+	Auction AuctionConfig `mapstructure:"auction"`
+	// DApps are served under /dapp/{name} next to the dApp of DAppPrivateKey
+	DApps []DAppConfig `mapstructure:"dapps"`
+	// Solvers are the solver identities the solver socket connections are bound to, next to the one of SolverPrivateKey
+	Solvers []SolverConfig `mapstructure:"solvers"`
+}
+
+// DAppConfig is a dApp the relay submits user operations for
+type DAppConfig struct {
+	// Name identifies the dApp in the route prefix /dapp/{name}
+	Name       string       `mapstructure:"name"`
+	PrivateKey string       `mapstructure:"private-key"`
+	Signer     SignerConfig `mapstructure:"signer"`
+	Address    string       `mapstructure:"address"`
+	// ChainID is the chain of the dApp user operations, it defaults to the Atlas chain id
+	ChainID uint64        `mapstructure:"chain-id"`
+	Auction AuctionConfig `mapstructure:"auction"`
+}
+
+// AllDApps returns the dApps of the dapps list, preceded by the dApp of the top level
+// dapp-private-key or dapp-signer and dapp-address under the default name, with their chain id defaulted
+func (c *Config) AllDApps() []DAppConfig {
+	dApps := make([]DAppConfig, 0, len(c.DApps)+1)
+
+	if c.DAppPrivateKey != "" || c.DAppSigner.Enabled() {
+		dApps = append(dApps, DAppConfig{
+			Name:       DefaultDAppName,
+			PrivateKey: c.DAppPrivateKey,
+			Signer:     c.DAppSigner,
+			Address:    c.DAppAddress,
+			Auction:    c.Auction,
+		})
+	}
+
+	dApps = append(dApps, c.DApps...)
+
+	for i := range dApps {
+		if dApps[i].ChainID == 0 {
+			dApps[i].ChainID = c.Atlas.ChainID
+		}
+	}
+
+	return dApps
+}
+
+// SolverConfig is a solver identity the relay submits solver operations under
+type SolverConfig struct {
+	// Name identifies the solver in the API keys and JWTs bound to it
+	Name       string       `mapstructure:"name"`
+	PrivateKey string       `mapstructure:"private-key"`
+	Signer     SignerConfig `mapstructure:"signer"`
+	// DAppAddresses are the dApps whose intents the solver subscribes to, it defaults to the top level dapp-address
+	DAppAddresses []string `mapstructure:"dapp-addresses"`
+}
+
+// AllSolvers returns the solvers of the solvers list, preceded by the solver of the top level
+// solver-private-key or solver-signer under the default name, with their dApp addresses defaulted
+func (c *Config) AllSolvers() []SolverConfig {
+	solvers := make([]SolverConfig, 0, len(c.Solvers)+1)
+
+	if c.SolverPrivateKey != "" || c.SolverSigner.Enabled() {
+		solvers = append(solvers, SolverConfig{
+			Name:       DefaultSolverName,
+			PrivateKey: c.SolverPrivateKey,
+			Signer:     c.SolverSigner,
+		})
+	}
+
+	solvers = append(solvers, c.Solvers...)
+
+	for i := range solvers {
+		if len(solvers[i].DAppAddresses) == 0 && c.DAppAddress != "" {
+			solvers[i].DAppAddresses = []string{c.DAppAddress}
+		}
+	}
+
+	return solvers
+}
+
+// SignerConfig keeps a key out of the config in plaintext, the key is read from an encrypted keystore
+// or its messages are signed by a remote signer
+type SignerConfig struct {
+	// Keystore is the path of a go-ethereum encrypted keystore JSON file, decrypted with the password of PasswordFile
+	Keystore     string `mapstructure:"keystore"`
+	PasswordFile string `mapstructure:"password-file"`
+	// RemoteURL is the JSON-RPC URL of a signer speaking the relay signer protocol, holding the key of Account
+	RemoteURL string `mapstructure:"remote-url"`
+	Account   string `mapstructure:"account"`
+}
+
+// Enabled reports whether the key is read from a keystore or held by a remote signer
+func (c *SignerConfig) Enabled() bool {
+	return c.Keystore != "" || c.RemoteURL != ""
+}
+
+// validateKey checks that the key is either the plaintext private key, a keystore or a remote signer
+func validateKey(privateKey string, signer *SignerConfig) error {
+	if (privateKey != "" && signer.Enabled()) || (signer.Keystore != "" && signer.RemoteURL != "") {
+		return ErrMultipleKeySources
+	}
+
+	if signer.RemoteURL != "" && !common.IsHexAddress(signer.Account) {
+		return ErrSignerAccountRequired
+	}
+
+	return nil
+}
+
+// StoreType selects where the intents submitted through the relay and their solutions are kept
+type StoreType string
+
+const (
+	StoreTypeMemory  StoreType = "memory"
+	StoreTypeLevelDB StoreType = "leveldb"
+)
+
+// StoreConfig configures the store of the intents submitted through the relay and their solutions
+type StoreConfig struct {
+	// Type is memory, the default when empty, or leveldb to keep the intents across restarts
+	Type StoreType `mapstructure:"type"`
+	// Path is the directory of the LevelDB database
+	Path string `mapstructure:"path"`
+	// Retention is how long intents are kept after their submission
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// SlowConsumerPolicy is what happens to a notification for a subscription whose buffer is full
+type SlowConsumerPolicy string
+
+const (
+	// SlowConsumerPolicyDropNewest drops the notification, the default
+	SlowConsumerPolicyDropNewest SlowConsumerPolicy = "drop-newest"
+	// SlowConsumerPolicyDropOldest drops the oldest buffered notification to make room for it
+	SlowConsumerPolicyDropOldest SlowConsumerPolicy = "drop-oldest"
+	// SlowConsumerPolicyDisconnect disconnects the client
+	SlowConsumerPolicyDisconnect SlowConsumerPolicy = "disconnect"
+)
+
+// DefaultSubscriptionBufferSize is the number of notifications buffered per subscription when the size is not set
+const DefaultSubscriptionBufferSize = 10000
+
+// SubscriptionsConfig configures the notification buffers of the solver subscriptions
+type SubscriptionsConfig struct {
+	// BufferSize is the number of notifications buffered per subscription, DefaultSubscriptionBufferSize when 0
+	BufferSize int `mapstructure:"buffer-size"`
+	// SlowConsumerPolicy applies once the buffer of a subscription is full, drop-newest when empty
+	SlowConsumerPolicy SlowConsumerPolicy `mapstructure:"slow-consumer-policy"`
+	// StatusInterval is the period of the status notifications of the subscriptions, they are not sent when 0
+	StatusInterval time.Duration `mapstructure:"status-interval"`
+	// MaxLag disconnects the clients whose subscription buffer overflowed and was not drained within it, disabled when 0
+	MaxLag time.Duration `mapstructure:"max-lag"`
+	// ReplayBufferSize is the number of recent intent notifications kept for the subscriptions resuming
+	// from a sequence number, the intents are not replayed when 0
+	ReplayBufferSize int `mapstructure:"replay-buffer-size"`
+}
+
+// Buffer returns the number of notifications buffered per subscription
+func (c *SubscriptionsConfig) Buffer() int {
+	if c.BufferSize == 0 {
+		return DefaultSubscriptionBufferSize
+	}
+
+	return c.BufferSize
+}
+
+// Policy returns the slow consumer policy of the subscriptions
+func (c *SubscriptionsConfig) Policy() SlowConsumerPolicy {
+	if c.SlowConsumerPolicy == "" {
+		return SlowConsumerPolicyDropNewest
+	}
+
+	return c.SlowConsumerPolicy
+}
+
+func (c *SubscriptionsConfig) validate() error {
+	if c.BufferSize < 0 || c.ReplayBufferSize < 0 || c.StatusInterval < 0 || c.MaxLag < 0 {
+		return ErrInvalidSubscriptions
+	}
+
+	switch c.Policy() {
+	case SlowConsumerPolicyDropNewest, SlowConsumerPolicyDropOldest, SlowConsumerPolicyDisconnect:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidSlowConsumerPolicy, c.SlowConsumerPolicy)
+	}
+}
+
+// MaxAuctionDuration is the longest auction window, the solutions of an intent are only tracked live for a minute
+const MaxAuctionDuration = time.Minute
+
+// AuctionConfig is the auction window of the intents of a dApp, the auction is disabled when DurationMS is 0
+type AuctionConfig struct {
+	// DurationMS is how long the solutions of an intent are collected after its submission before the result is frozen
+	DurationMS int `mapstructure:"duration-ms"`
+	// MinBid drops the solver operations bidding less, a decimal amount in wei
+	MinBid string `mapstructure:"min-bid"`
+	// MaxSolvers caps the number of solver operations of the result, unlimited when 0
+	MaxSolvers int `mapstructure:"max-solvers"`
+}
+
+// Enabled reports whether the intents of the dApp are auctioned
+func (c *AuctionConfig) Enabled() bool {
+	return c.DurationMS > 0
+}
+
+// Duration returns the auction window
+func (c *AuctionConfig) Duration() time.Duration {
+	return time.Duration(c.DurationMS) * time.Millisecond
+}
+
+// MinBidAmount returns the minimum bid in wei, nil when any bid is accepted
+func (c *AuctionConfig) MinBidAmount() *big.Int {
+	if c.MinBid == "" {
+		return nil
+	}
+
+	amount, ok := new(big.Int).SetString(c.MinBid, 10)
+	if !ok {
+		return nil
+	}
+
+	return amount
+}
+
+func (c *AuctionConfig) validate() error {
+	if c.DurationMS < 0 || c.Duration() > MaxAuctionDuration {
+		return fmt.Errorf("%w: %d", ErrInvalidAuctionDuration, c.DurationMS)
+	}
+
+	if c.MaxSolvers < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidAuctionMaxSolvers, c.MaxSolvers)
+	}
+
+	if amount, ok := new(big.Int).SetString(c.MinBid, 10); c.MinBid != "" && (!ok || amount.Sign() < 0) {
+		return fmt.Errorf("%w: %q", ErrInvalidAuctionMinBid, c.MinBid)
+	}
+
+	return nil
+}
+
+// SimulationMode controls the simulation of solver operations before they are returned to the dApp
+type SimulationMode string
+
+const (
+	SimulationModeDisabled SimulationMode = ""
+	// SimulationModeMark attaches the simulation result to every solver operation
+	SimulationModeMark SimulationMode = "mark"
+	// SimulationModeFilter additionally drops the solver operations that fail the simulation
+	SimulationModeFilter SimulationMode = "filter"
+)
+
+type AtlasConfig struct {
+	ChainID        uint64         `mapstructure:"chain-id"`
+	EthRPCURL      string         `mapstructure:"eth-rpc-url"`
+	SimulationMode SimulationMode `mapstructure:"simulation-mode"`
+}
+
+// Permission grants access to a group of routes of the relay API
+type Permission string
+
+const (
+	// PermissionDApp grants access to the dApp routes
+	PermissionDApp Permission = "dapp"
+	// PermissionSolver grants access to the solver socket
+	PermissionSolver Permission = "solver"
+	// PermissionAdmin grants access to the operator routes, such as the metrics
+	PermissionAdmin Permission = "admin"
+)
+
+// AuthConfig configures the authentication of the relay API, it is disabled when no API key and no JWT secret are set
+type AuthConfig struct {
+	APIKeys []APIKeyConfig `mapstructure:"api-keys"`
+	// JWTSecret is the HMAC secret of the JWTs accepted as credentials, their permissions claim lists the granted permissions
+	// and their dapps claim the dApps they may use
+	JWTSecret string `mapstructure:"jwt-secret"`
+}
+
+// Enabled reports whether the relay API requires authentication
+func (c *AuthConfig) Enabled() bool {
+	return len(c.APIKeys) != 0 || c.JWTSecret != ""
+}
+
+type APIKeyConfig struct {
+	// Name identifies the API key holder in the logs
+	Name        string       `mapstructure:"name"`
+	Key         string       `mapstructure:"key"`
+	Permissions []Permission `mapstructure:"permissions"`
+	// Solver is the solver the solver socket connections of the key are bound to, the default solver when empty
+	Solver string `mapstructure:"solver"`
+	// DApps are the names of the dApps the key may use with the dapp permission, the default dApp only when empty
+	DApps []string `mapstructure:"dapps"`
+}
+
+// RateLimitConfig limits the requests of every client, identified by its API key or its remote address.
+// Routes and methods without a limit are not limited
+type RateLimitConfig struct {
+	// Routes maps the route names, such as SubmitUserOperation, to their limit
+	Routes map[string]RateLimit `mapstructure:"routes"`
+	// Methods maps the JSON-RPC methods, such as submitSolverOperation, to their limit
+	Methods map[string]RateLimit `mapstructure:"methods"`
+	// GRPC maps the gRPC methods, such as SubmitSolverOperation, to their limit
+	GRPC map[string]RateLimit `mapstructure:"grpc"`
+}
+
+// RateLimit is a token bucket refilled with Rate tokens per second up to Burst tokens
+type RateLimit struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
 }
 
 type BDNConfig struct {
-	WSURL      string `mapstructure:"ws-url"`
-	GRPCURL    string `mapstructure:"grpc-url"`
-	AuthHeader string `mapstructure:"auth-header"`
+	WSURL   string `mapstructure:"ws-url"`
+	GRPCURL string `mapstructure:"grpc-url"`
+	// GRPCInsecure disables TLS on the gRPC connection, local gateways serve gRPC without TLS
+	GRPCInsecure bool   `mapstructure:"grpc-insecure"`
+	AuthHeader   string `mapstructure:"auth-header"`
 }
 
 func Read(vip *viper.Viper) (*Config, error) {
@@ -106,6 +468,32 @@ func hasSupportedConfigExtension(configExt string) bool {
 	return false
 }
 
+// TrustedProxyPrefixes returns the trusted proxies as prefixes, an address is a single address prefix
+func (c *Config) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.TrustedProxies))
+
+	for _, proxy := range c.TrustedProxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidTrustedProxy, proxy)
+			}
+
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTrustedProxy, proxy)
+		}
+
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+
+	return prefixes, nil
+}
+
 func validate(cfg *Config) error {
 	if cfg.BDN.WSURL == "" && cfg.BDN.GRPCURL == "" {
 		return ErrBDNURLRequired
@@ -115,13 +503,141 @@ func validate(cfg *Config) error {
 		return ErrBDNAuthHeaderRequired
 	}
 
-	if cfg.DAppPrivateKey == "" && cfg.SolverPrivateKey == "" {
+	dApps := cfg.AllDApps()
+	solvers := cfg.AllSolvers()
+
+	if len(dApps) == 0 && len(solvers) == 0 {
 		return ErrPrivateKeyRequired
 	}
 
-	if cfg.SolverPrivateKey != "" && cfg.DAppAddress == "" {
-		return ErrDAppAddressRequired
+	// the chain id selects the EIP-712 domain solver operations are verified against
+	_, err := atlasconfig.GetEip712Domain(cfg.Atlas.ChainID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedChainID, err)
+	}
+
+	names := make(map[string]struct{}, len(dApps))
+	for _, dApp := range dApps {
+		if _, ok := names[dApp.Name]; ok || !namePattern.MatchString(dApp.Name) {
+			return fmt.Errorf("%w: %q", ErrInvalidDAppName, dApp.Name)
+		}
+		names[dApp.Name] = struct{}{}
+
+		if dApp.PrivateKey == "" && !dApp.Signer.Enabled() {
+			return fmt.Errorf("%w: %s", ErrDAppKeyRequired, dApp.Name)
+		}
+
+		err = validateKey(dApp.PrivateKey, &dApp.Signer)
+		if err != nil {
+			return fmt.Errorf("dApp %s: %w", dApp.Name, err)
+		}
+
+		_, err = atlasconfig.GetEip712Domain(dApp.ChainID)
+		if err != nil {
+			return fmt.Errorf("%w: dApp %s: %v", ErrUnsupportedChainID, dApp.Name, err)
+		}
+
+		err = dApp.Auction.validate()
+		if err != nil {
+			return fmt.Errorf("dApp %s: %w", dApp.Name, err)
+		}
+	}
+
+	solverNames := make(map[string]struct{}, len(solvers))
+	for _, solver := range solvers {
+		if _, ok := solverNames[solver.Name]; ok || !namePattern.MatchString(solver.Name) {
+			return fmt.Errorf("%w: %q", ErrInvalidSolverName, solver.Name)
+		}
+		solverNames[solver.Name] = struct{}{}
+
+		if solver.PrivateKey == "" && !solver.Signer.Enabled() {
+			return fmt.Errorf("%w: %s", ErrSolverKeyRequired, solver.Name)
+		}
+
+		err = validateKey(solver.PrivateKey, &solver.Signer)
+		if err != nil {
+			return fmt.Errorf("solver %s: %w", solver.Name, err)
+		}
+
+		if len(solver.DAppAddresses) == 0 {
+			return fmt.Errorf("%w: solver %s", ErrDAppAddressRequired, solver.Name)
+		}
+
+		for _, address := range solver.DAppAddresses {
+			if !common.IsHexAddress(address) {
+				return fmt.Errorf("%w: solver %s: %q", ErrInvalidDAppAddress, solver.Name, address)
+			}
+		}
+	}
+
+	_, err = cfg.TrustedProxyPrefixes()
+	if err != nil {
+		return err
+	}
+
+	err = cfg.Subscriptions.validate()
+	if err != nil {
+		return err
+	}
+
+	switch cfg.Store.Type {
+	case "", StoreTypeMemory:
+	case StoreTypeLevelDB:
+		if cfg.Store.Path == "" {
+			return ErrStorePathRequired
+		}
+	default:
+		return ErrInvalidStoreType
+	}
+
+	switch cfg.Atlas.SimulationMode {
+	case SimulationModeDisabled:
+	case SimulationModeMark, SimulationModeFilter:
+		if cfg.Atlas.EthRPCURL == "" {
+			return ErrEthRPCURLRequired
+		}
+	default:
+		return ErrInvalidSimulationMode
+	}
+
+	for _, apiKey := range cfg.Auth.APIKeys {
+		if apiKey.Name == "" || apiKey.Key == "" {
+			return ErrInvalidAPIKey
+		}
+
+		for _, permission := range apiKey.Permissions {
+			if !validPermission(permission) {
+				return fmt.Errorf("%w: %s", ErrInvalidPermission, permission)
+			}
+		}
+
+		if _, ok := solverNames[apiKey.Solver]; apiKey.Solver != "" && !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownSolver, apiKey.Solver)
+		}
+
+		for _, dApp := range apiKey.DApps {
+			if _, ok := names[dApp]; !ok {
+				return fmt.Errorf("%w: %s", ErrUnknownDApp, dApp)
+			}
+		}
+	}
+
+	for _, limits := range []map[string]RateLimit{cfg.RateLimit.Routes, cfg.RateLimit.Methods, cfg.RateLimit.GRPC} {
+		for name, limit := range limits {
+			if limit.Rate <= 0 || limit.Burst <= 0 {
+				return fmt.Errorf("%w: %s", ErrInvalidRateLimit, name)
+			}
+		}
 	}
 
 	return nil
 }
+
+func validPermission(permission Permission) bool {
+	switch permission {
+	case PermissionDApp, PermissionSolver, PermissionAdmin:
+		return true
+	default:
+		return false
+	}
+}
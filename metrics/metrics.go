@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bdn_operations_relay"
+
+var (
+	UserOperationsSubmitted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "user_operations_submitted_total",
+		Help:      "Number of user operations submitted by dApps as intents to the BDN",
+	}, []string{"status"})
+
+	IntentsReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "intents_received_total",
+		Help:      "Number of intents received from the BDN",
+	})
+
+	SolverOperationsSubmitted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "solver_operations_submitted_total",
+		Help:      "Number of solver operations submitted by solvers to the BDN",
+	}, []string{"status"})
+
+	SolverOperationsReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "solver_operations_received_total",
+		Help:      "Number of solver operations received from the BDN",
+	})
+
+	SolverOperationsPerIntent = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "solver_operations_per_intent",
+		Help:      "Number of solver operations received for an intent submitted through the relay, observed when the intent expires",
+		Buckets:   []float64{0, 1, 2, 3, 5, 10, 20, 50},
+	})
+
+	BDNRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "bdn_request_duration_seconds",
+		Help:      "Latency of the requests to the BDN gateway",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "status"})
+
+	ActiveSubscriptions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_subscriptions",
+		Help:      "Number of active WebSocket subscriptions",
+	}, []string{"type"})
+
+	NotificationsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_dropped_total",
+		Help:      "Number of notifications dropped because the subscriber did not keep up",
+	}, []string{"type"})
+
+	ClientsEvicted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "clients_evicted_total",
+		Help:      "Number of clients disconnected because they did not keep up with their notifications",
+	})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the HTTP requests by route and status code",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	RequestsRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_rate_limited_total",
+		Help:      "Number of HTTP requests and JSON-RPC calls rejected by the rate limits, by route or method",
+	}, []string{"name"})
+)
+
+// Handler returns the HTTP handler exporting the metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Status returns the status label of an operation
+func Status(err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	return "success"
+}
+
+// ObserveBDNRequest records the latency of a BDN request that started at start
+func ObserveBDNRequest(method string, start time.Time, err error) {
+	BDNRequestDuration.WithLabelValues(method, Status(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveHTTPRequest records the latency of an HTTP request that started at start
+func ObserveHTTPRequest(route, method string, status int, start time.Time) {
+	HTTPRequestDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+}
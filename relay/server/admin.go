@@ -0,0 +1,10 @@
+package server
+
+import (
+	"net/http"
+)
+
+// listConnections lists the live client connections holding subscriptions
+func (s *Server) listConnections(w http.ResponseWriter, _ *http.Request) {
+	writeResponseData(w, s.subscriptionService.Connections())
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+var (
+	errMissingCredentials = errors.New("missing credentials")
+	errInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Identity is the authenticated caller of the relay API
+type Identity struct {
+	Name        string
+	Permissions []config.Permission
+	// Solver is the solver the solver socket connections of the caller are bound to, the default solver when empty
+	Solver string
+	// DApps are the dApps the caller may use, the default dApp only when empty
+	DApps []string
+}
+
+// HasPermission reports whether the identity was granted the permission
+func (i *Identity) HasPermission(permission config.Permission) bool {
+	return slices.Contains(i.Permissions, permission)
+}
+
+// AllowsDApp reports whether the identity may use the dApp
+func (i *Identity) AllowsDApp(name string) bool {
+	if len(i.DApps) == 0 {
+		return name == config.DefaultDAppName
+	}
+
+	return slices.Contains(i.DApps, name)
+}
+
+// Authenticator authenticates the caller of a request from the token it carries
+type Authenticator interface {
+	// Authenticate returns the identity the token belongs to, or an error when it does not recognize the token
+	Authenticate(token string) (*Identity, error)
+}
+
+type identityKey struct{}
+
+// identityFromContext returns the name of the authenticated caller, empty when authentication is disabled
+func identityFromContext(ctx context.Context) string {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	if !ok {
+		return ""
+	}
+
+	return identity.Name
+}
+
+// solverFromContext returns the name of the solver the caller is bound to
+func solverFromContext(ctx context.Context) string {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	if !ok || identity.Solver == "" {
+		return config.DefaultSolverName
+	}
+
+	return identity.Solver
+}
+
+// dAppAllowed reports whether the caller may use the dApp, any dApp may be used when authentication is disabled
+func dAppAllowed(ctx context.Context, name string) bool {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	if !ok {
+		return true
+	}
+
+	return identity.AllowsDApp(name)
+}
+
+// newAuthenticator returns the authenticator of the configured credentials, nil when authentication is disabled
+func newAuthenticator(cfg *config.AuthConfig) Authenticator {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	var authenticators multiAuthenticator
+
+	if len(cfg.APIKeys) != 0 {
+		authenticators = append(authenticators, newAPIKeyAuthenticator(cfg.APIKeys))
+	}
+
+	if cfg.JWTSecret != "" {
+		authenticators = append(authenticators, &jwtAuthenticator{secret: []byte(cfg.JWTSecret)})
+	}
+
+	return authenticators
+}
+
+// multiAuthenticator accepts a token recognized by any of its authenticators
+type multiAuthenticator []Authenticator
+
+func (m multiAuthenticator) Authenticate(token string) (*Identity, error) {
+	for _, authenticator := range m {
+		identity, err := authenticator.Authenticate(token)
+		if err == nil {
+			return identity, nil
+		}
+	}
+
+	return nil, errInvalidCredentials
+}
+
+// apiKeyAuthenticator accepts the static API keys of the config
+type apiKeyAuthenticator struct {
+	// identities are indexed by the SHA-256 hash of the key, so the lookup time does not depend on the key prefix
+	identities map[[sha256.Size]byte]*Identity
+}
+
+func newAPIKeyAuthenticator(apiKeys []config.APIKeyConfig) *apiKeyAuthenticator {
+	a := &apiKeyAuthenticator{
+		identities: make(map[[sha256.Size]byte]*Identity, len(apiKeys)),
+	}
+
+	for _, apiKey := range apiKeys {
+		a.identities[sha256.Sum256([]byte(apiKey.Key))] = &Identity{
+			Name:        apiKey.Name,
+			Permissions: apiKey.Permissions,
+			Solver:      apiKey.Solver,
+			DApps:       apiKey.DApps,
+		}
+	}
+
+	return a
+}
+
+func (a *apiKeyAuthenticator) Authenticate(token string) (*Identity, error) {
+	identity, ok := a.identities[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, errInvalidCredentials
+	}
+
+	return identity, nil
+}
+
+// jwtClaims are the claims of the JWTs accepted by the relay, the subject identifies the caller
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Permissions []config.Permission `json:"permissions"`
+	Solver      string              `json:"solver,omitempty"`
+	DApps       []string            `json:"dapps,omitempty"`
+}
+
+// jwtAuthenticator accepts the JWTs signed with the HMAC secret of the config
+type jwtAuthenticator struct {
+	secret []byte
+}
+
+func (a *jwtAuthenticator) Authenticate(token string) (*Identity, error) {
+	var claims jwtClaims
+
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+		}
+
+		return a.secret, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidCredentials, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: subject is required", errInvalidCredentials)
+	}
+
+	// the parser only checks the expiry when it is set, a JWT without one would never expire
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: expiry is required", errInvalidCredentials)
+	}
+
+	return &Identity{
+		Name:        claims.Subject,
+		Permissions: claims.Permissions,
+		Solver:      claims.Solver,
+		DApps:       claims.DApps,
+	}, nil
+}
+
+// requestToken returns the token of the X-API-Key header or the bearer token of the Authorization header
+func requestToken(r *http.Request) string {
+	if token := r.Header.Get(apiKeyHeader); token != "" {
+		return token
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+// authorize rejects the requests that do not carry credentials granting the permission
+// and adds the identity of the caller to the request context
+func (s *Server) authorize(inner http.Handler, permission config.Permission) http.Handler {
+	if s.authenticator == nil || permission == "" {
+		return inner
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := requestToken(r)
+		if token == "" {
+			writeErrResponse(w, http.StatusUnauthorized, errMissingCredentials.Error())
+			return
+		}
+
+		identity, err := s.authenticator.Authenticate(token)
+		if err != nil {
+			writeErrResponse(w, http.StatusUnauthorized, errInvalidCredentials.Error())
+			return
+		}
+
+		if rec, ok := w.(*statusRecorder); ok {
+			rec.identity = identity.Name
+		}
+
+		if !identity.HasPermission(permission) {
+			writeErrResponse(w, http.StatusForbidden, fmt.Sprintf("%s permission is required", permission))
+			return
+		}
+
+		inner.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, identity)))
+	})
+}
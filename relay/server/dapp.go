@@ -1,15 +1,49 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/FastLane-Labs/atlas-sdk-go/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/jsonrpc2"
+	ws "github.com/sourcegraph/jsonrpc2/websocket"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+)
+
+const (
+	maxSolverOperationsWait = 30 * time.Second
+	sseKeepAliveInterval    = 15 * time.Second
+
+	sseEventSolverOperation = "solverOperation"
+	sseEventIntentExpired   = "intentExpired"
+	sseEventAuctionClosed   = "auctionClosed"
+	sseEventDropped         = "dropped"
+
+	sortByBid = "bid"
+
+	// dAppNameVar is the route variable of the dApp name in the /dapp/{dapp} prefix
+	dAppNameVar = "dapp"
+	// intentIDVar is the route variable of the intent ID in the /intent/{id} route
+	intentIDVar = "id"
 )
 
 func (s *Server) userOperation(w http.ResponseWriter, r *http.Request) {
+	dApp, ok := s.dApp(w, r)
+	if !ok {
+		return
+	}
+
 	var req types.UserOperationWithHintsRaw
 	err := parseRequest(r, &req)
 	if err != nil {
@@ -19,49 +53,378 @@ func (s *Server) userOperation(w http.ResponseWriter, r *http.Request) {
 	}
 
 	chainID, userOp, hints := req.Decode()
-	partialOperation, err := types.NewUserOperationPartialRaw(chainID, userOp, hints)
+	intentID, err := dApp.SubmitUserOperation(r.Context(), chainID, userOp, hints)
+	if err != nil {
+		log.Error("failed to submit user operation", "error", err)
+		if errors.Is(err, service.ErrInvalidUserOperation) {
+			writeErrResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeInternalErrResponse(w)
+		return
+	}
+
+	writeResponseData(w, map[string]string{
+		"intent_id": intentID,
+	})
+}
+
+func (s *Server) solverOperations(w http.ResponseWriter, r *http.Request) {
+	dApp, ok := s.dApp(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	intentID := q.Get("intent_id")
+	if intentID == "" {
+		log.Error("intent_id is required")
+		writeErrResponse(w, http.StatusBadRequest, "intent_id is required")
+		return
+	}
+
+	waitMS, err := parseIntParam(q, "wait_ms", 0)
 	if err != nil {
-		log.Error("failed to create user operation partial", "error", err)
-		writeErrResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid user operation parameters: %v", err))
+		writeErrResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	data, err := json.Marshal(partialOperation)
+	minSolutions, err := parseIntParam(q, "min_solutions", 1)
 	if err != nil {
-		log.Error("failed to marshal user operation partial", "error", err)
-		writeInternalErrResponse(w)
+		writeErrResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, err := parseIntParam(q, "limit", 0)
+	if err != nil {
+		writeErrResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sortBy := q.Get("sort")
+	if sortBy != "" && sortBy != sortByBid {
+		writeErrResponse(w, http.StatusBadRequest, fmt.Sprintf("unsupported sort value: %s", sortBy))
 		return
 	}
 
-	intentID, err := s.intentService.SubmitIntent(r.Context(), data)
+	simulated, err := querySolverOperations(r.Context(), dApp, &solverOperationsQuery{
+		intentID:     intentID,
+		wait:         time.Duration(waitMS) * time.Millisecond,
+		minSolutions: minSolutions,
+		limit:        limit,
+		sortByBid:    sortBy == sortByBid,
+	})
 	if err != nil {
-		log.Error("failed to submit intent", "error", err)
+		log.Error("failed to get intent solutions", "error", err)
 		writeInternalErrResponse(w)
 		return
 	}
 
-	s.intentService.SubscribeToIntentSolutions(intentID)
+	writeResponseData(w, simulated)
+}
+
+// solverOperationsQuery selects the solver operations of an intent returned to the dApp
+type solverOperationsQuery struct {
+	intentID string
+	// wait is how long to wait for minSolutions solver operations, the operations received so far are returned when 0
+	wait         time.Duration
+	minSolutions int
+	// limit caps the number of solver operations returned, all of them are returned when 0
+	limit     int
+	sortByBid bool
+}
 
-	writeResponseData(w, map[string]string{
-		"intent_id": intentID,
+// querySolverOperations returns the solver operations of an intent with their simulation results,
+// it backs both the HTTP and the gRPC APIs
+func querySolverOperations(ctx context.Context, dApp *service.DApp, q *solverOperationsQuery) ([]service.SimulatedSolverOperation, error) {
+	var (
+		resp []types.SolverOperationRaw
+		err  error
+	)
+
+	// the intents the relay did not auction serve the solutions received from the BDN
+	auctioned := dApp.Auctioned(q.intentID)
+
+	if auctioned {
+		resp = auctionSolverOperations(ctx, dApp, q)
+	} else if q.wait > 0 {
+		waitCtx, cancel := context.WithTimeout(ctx, min(q.wait, maxSolverOperationsWait))
+		defer cancel()
+
+		resp, err = dApp.WaitForIntentSolutions(waitCtx, q.intentID, q.minSolutions)
+	} else {
+		resp, err = dApp.GetIntentSolutions(ctx, q.intentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the auction result is already ranked by bid
+	if q.sortByBid && !auctioned {
+		service.SortSolverOperationsByBid(resp)
+	}
+
+	return dApp.SimulateSolverOperations(ctx, q.intentID, resp, q.limit), nil
+}
+
+// auctionSolverOperations returns the result of the auction of the intent, waiting for the auction to close
+// within the wait of the query. Nothing is returned while the auction is open, minSolutions is not used
+func auctionSolverOperations(ctx context.Context, dApp *service.DApp, q *solverOperationsQuery) []types.SolverOperationRaw {
+	if q.wait > 0 {
+		waitCtx, cancel := context.WithTimeout(ctx, min(q.wait, maxSolverOperationsWait))
+		defer cancel()
+
+		if auction, ok := dApp.WaitForAuction(waitCtx, q.intentID); ok {
+			return auction.SolverOperations
+		}
+
+		return []types.SolverOperationRaw{}
+	}
+
+	if auction, ok := dApp.Auction(q.intentID); ok {
+		return auction.SolverOperations
+	}
+
+	return []types.SolverOperationRaw{}
+}
+
+func (s *Server) bundleOperations(w http.ResponseWriter, r *http.Request) {
+	dApp, ok := s.dApp(w, r)
+	if !ok {
+		return
+	}
+
+	var req bundleOperationsRequest
+	err := parseRequest(r, &req)
+	if err != nil {
+		log.Error("failed to parse request", "error", err)
+		writeErrResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+
+	bundle, err := dApp.BuildBundle(r.Context(), &service.BundleParams{
+		IntentID:              req.IntentID,
+		SolverOperationHashes: req.SolverOperationHashes,
+		Bundler:               req.Bundler,
+		Nonce:                 req.Nonce.ToInt(),
 	})
+	if err != nil {
+		log.Error("failed to build bundle", "error", err, "intent_id", req.IntentID)
+		switch {
+		case errors.Is(err, service.ErrIntentNotFound):
+			writeErrResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrSolverOperationNotFound), errors.Is(err, service.ErrNoSolverOperations):
+			writeErrResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrAuctionOpen):
+			writeErrResponse(w, http.StatusConflict, err.Error())
+		default:
+			writeInternalErrResponse(w)
+		}
+		return
+	}
+
+	writeResponseData(w, bundle)
 }
 
-func (s *Server) solverOperations(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query()
-	intentID := q.Get("intent_id")
+func (s *Server) intentLifecycle(w http.ResponseWriter, r *http.Request) {
+	dApp, ok := s.dApp(w, r)
+	if !ok {
+		return
+	}
+
+	intentID := mux.Vars(r)[intentIDVar]
+
+	lifecycle, err := dApp.IntentLifecycle(intentID)
+	if err != nil {
+		if errors.Is(err, service.ErrIntentNotFound) {
+			writeErrResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		log.Error("failed to get intent lifecycle", "error", err, "intent_id", intentID)
+		writeInternalErrResponse(w)
+		return
+	}
+
+	writeResponseData(w, lifecycle)
+}
+
+func (s *Server) websocketDApp(w http.ResponseWriter, r *http.Request) {
+	dApp, ok := s.dApp(w, r)
+	if !ok {
+		return
+	}
+
+	connection, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Error("failed upgrading connection", "err", err)
+		writeInternalErrResponse(w)
+
+		return
+	}
+
+	h := newDAppConnHandler(s.clientAddress(r), r.RemoteAddr, identityFromContext(r.Context()), s.intentService,
+		s.subscriptionService, dApp, s.rateLimiter, func() { _ = connection.Close() })
+
+	asyncHandler := jsonrpc2.AsyncHandler(h)
+	conn := jsonrpc2.NewConn(context.Background(), ws.NewObjectStream(connection), asyncHandler)
+
+	go h.closeOnDisconnect(conn)
+}
+
+// streamSolverOperations streams the solver operations of an intent as Server-Sent Events
+// until the intent expires or the client disconnects
+func (s *Server) streamSolverOperations(w http.ResponseWriter, r *http.Request) {
+	dApp, ok := s.dApp(w, r)
+	if !ok {
+		return
+	}
+
+	intentID := r.URL.Query().Get("intent_id")
 	if intentID == "" {
 		log.Error("intent_id is required")
 		writeErrResponse(w, http.StatusBadRequest, "intent_id is required")
 		return
 	}
 
-	resp, err := s.intentService.GetIntentSolutions(r.Context(), intentID)
-	if err != nil {
-		log.Error("failed to get intent solutions", "error", err)
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Error("streaming is not supported by the response writer")
 		writeInternalErrResponse(w)
 		return
 	}
 
-	writeResponseData(w, resp)
+	solutions, watcher, ok, stop := dApp.WatchIntentSolutions(intentID)
+	defer stop()
+
+	if !ok {
+		writeErrResponse(w, http.StatusNotFound, "intent is not tracked by the relay")
+		return
+	}
+
+	// the stream is listed with the live connections, it ends when the relay shuts down
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	connectionID := s.subscriptionService.ConnectDApp(service.ConnectionInfo{
+		Kind:          service.ConnectionKindSolverOperationsStream,
+		RemoteAddress: s.clientAddress(r),
+		Identity:      identityFromContext(r.Context()),
+		DApp:          dApp.Name(),
+	}, func() int { return 1 }, cancel)
+	defer s.subscriptionService.Disconnect(connectionID)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for i := range solutions {
+		if err := writeEvent(w, sseEventSolverOperation, solutions[i]); err != nil {
+			log.Debug("failed to write solver operation event", "error", err)
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	auctionClosed := dApp.AuctionClosed(intentID)
+
+	// reported is the number of dropped solver operations the client was told about
+	var reported uint64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-auctionClosed:
+			// the channel stays closed, the event is only sent once
+			auctionClosed = nil
+
+			if auction, ok := dApp.Auction(intentID); ok {
+				if err := writeEvent(w, sseEventAuctionClosed, newAuctionResult(intentID, auction)); err != nil {
+					log.Debug("failed to write auction closed event", "error", err)
+					return
+				}
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case solution, open := <-watcher.Updates:
+			if !open {
+				_ = writeEvent(w, sseEventIntentExpired, map[string]string{"intent_id": intentID})
+				flusher.Flush()
+				return
+			}
+
+			if err := writeEvent(w, sseEventSolverOperation, solution); err != nil {
+				log.Debug("failed to write solver operation event", "error", err)
+				return
+			}
+		}
+
+		// the client is told about the solver operations it missed, they are returned by GET /solverOperations
+		if dropped := watcher.Dropped(); dropped != reported {
+			reported = dropped
+
+			if err := writeEvent(w, sseEventDropped, droppedEvent{IntentID: intentID, Dropped: dropped}); err != nil {
+				log.Debug("failed to write dropped event", "error", err)
+				return
+			}
+		}
+
+		flusher.Flush()
+	}
+}
+
+// dApp returns the dApp of the request, the routes without the /dapp/{dapp} prefix serve the default dApp.
+// It responds with 403 Forbidden when the caller may not use the dApp and 404 Not Found when no dApp
+// is configured under the name
+func (s *Server) dApp(w http.ResponseWriter, r *http.Request) (*service.DApp, bool) {
+	name, ok := mux.Vars(r)[dAppNameVar]
+	if !ok {
+		name = config.DefaultDAppName
+	}
+
+	if !dAppAllowed(r.Context(), name) {
+		writeErrResponse(w, http.StatusForbidden, fmt.Sprintf("dApp %s is not allowed", name))
+		return nil, false
+	}
+
+	dApp, ok := s.intentService.DApp(name)
+	if !ok {
+		writeErrResponse(w, http.StatusNotFound, fmt.Sprintf("unknown dApp %s", name))
+		return nil, false
+	}
+
+	return dApp, true
+}
+
+func writeEvent(w http.ResponseWriter, event string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %v", err)
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+
+	return err
+}
+
+func parseIntParam(q url.Values, name string, defaultValue int) (int, error) {
+	value := q.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+
+	return v, nil
 }
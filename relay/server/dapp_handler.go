@@ -0,0 +1,458 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/google/uuid"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/valyala/fastjson"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+)
+
+const (
+	methodSubmitUserOperation = "submitUserOperation"
+	methodGetSolverOperations = "getSolverOperations"
+
+	subscriptionTypeSolutions = "solutions"
+)
+
+var userOperationMissingErrMsg = "userOperation and chainId values are required"
+
+type wsDAppConnHandler struct {
+	// remoteAddress is the address of the client, logged as the caller
+	remoteAddress string
+	// peerAddress is the address the connection comes from, a proxy when the client is behind one, rate limits apply to it
+	peerAddress string
+	// identity is the authenticated caller, empty when authentication is disabled
+	identity      string
+	intentService *service.Intent
+	dApp          *service.DApp
+	rateLimiter   *rateLimiter
+	// connectionID is the ID of the connection in the live connections of subscriptionService
+	connectionID        string
+	subscriptionService *service.SubscriptionManager
+
+	lock          sync.Mutex
+	subscriptions map[string]func()
+}
+
+// newDAppConnHandler returns the handler of a dApp connection, registered in the live connections of subscriptionService.
+// closeConn closes the connection of the client when the relay shuts down
+func newDAppConnHandler(remoteAddress, peerAddress, identity string, intentService *service.Intent,
+	subscriptionService *service.SubscriptionManager, dApp *service.DApp, rateLimiter *rateLimiter, closeConn func()) *wsDAppConnHandler {
+	h := &wsDAppConnHandler{
+		remoteAddress:       remoteAddress,
+		peerAddress:         peerAddress,
+		identity:            identity,
+		intentService:       intentService,
+		dApp:                dApp,
+		rateLimiter:         rateLimiter,
+		subscriptionService: subscriptionService,
+		subscriptions:       make(map[string]func()),
+	}
+
+	h.connectionID = subscriptionService.ConnectDApp(service.ConnectionInfo{
+		Kind:          service.ConnectionKindDAppSocket,
+		RemoteAddress: remoteAddress,
+		Identity:      identity,
+		DApp:          dApp.Name(),
+	}, h.subscriptionCount, closeConn)
+
+	return h
+}
+
+func (h *wsDAppConnHandler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	method := req.Method
+
+	if delay := h.rateLimiter.Reserve(limitMethod, method, clientKey(h.identity, h.peerAddress)); delay > 0 {
+		h.sendErrorMsg(ctx, codeRateLimited, rateLimitedErrMsg(delay), conn, req.ID)
+		return
+	}
+
+	switch method {
+	case methodPing:
+		response := pingResponse{
+			Pong:               time.Now().UTC().Format(microSecTimeFormat),
+			BDNConnectionState: string(h.intentService.ConnectionState()),
+		}
+		if err := conn.Reply(ctx, req.ID, response); err != nil {
+			logger.Error("error replying to client", "err", err, "reqID", req.ID, "caller", h.remoteAddress)
+		}
+	case methodSubmitUserOperation:
+		h.handleSubmitUserOperation(ctx, conn, req)
+	case methodGetSolverOperations:
+		h.handleGetSolverOperations(ctx, conn, req)
+	case methodSubscribe:
+		h.handleSubscribe(ctx, conn, req)
+	case methodUnsubscribe:
+		h.handleUnsubscribe(ctx, conn, req)
+	default:
+		h.sendErrorMsg(ctx, jsonrpc2.CodeMethodNotFound, "unsupported method name: "+method, conn, req.ID)
+	}
+}
+
+// handleSubmitUserOperation handles the submitUserOperation method
+func (h *wsDAppConnHandler) handleSubmitUserOperation(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, "params value is missing", conn, req.ID)
+		return
+	}
+
+	var params types.UserOperationWithHintsRaw
+	err := unmarshalParams(*req.Params, &params)
+	if err != nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, fmt.Sprintf("failed to parse params: %v", err), conn, req.ID)
+		return
+	}
+
+	if params.UserOperation == nil || params.ChainId == nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, userOperationMissingErrMsg, conn, req.ID)
+		return
+	}
+
+	chainID, userOp, hints := params.Decode()
+	intentID, err := h.dApp.SubmitUserOperation(ctx, chainID, userOp, hints)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUserOperation) {
+			h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, err.Error(), conn, req.ID)
+			return
+		}
+
+		logger.Error("failed to submit user operation", "error", err, "caller", h.remoteAddress)
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInternalError, "failed to submit user operation", conn, req.ID)
+		return
+	}
+
+	response := submitUserOperationResponse{
+		IntentID: intentID,
+	}
+
+	if err = conn.Reply(ctx, req.ID, response); err != nil {
+		logger.Error("error replying to client", "err", err, "reqID", req.ID, "caller", h.remoteAddress)
+	}
+}
+
+// handleGetSolverOperations handles the getSolverOperations method, it takes the parameters of GET /solverOperations
+func (h *wsDAppConnHandler) handleGetSolverOperations(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	q, ok := h.parseSolverOperationsQuery(ctx, conn, req)
+	if !ok {
+		return
+	}
+
+	solverOperations, err := querySolverOperations(ctx, h.dApp, q)
+	if err != nil {
+		logger.Error("failed to get intent solutions", "error", err, "caller", h.remoteAddress)
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInternalError, "failed to get solver operations", conn, req.ID)
+		return
+	}
+
+	if err = conn.Reply(ctx, req.ID, solverOperations); err != nil {
+		logger.Error("error replying to client", "err", err, "reqID", req.ID, "caller", h.remoteAddress)
+	}
+}
+
+// handleSubscribe handles the subscribe method, only the solutions subscription type is supported
+func (h *wsDAppConnHandler) handleSubscribe(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, "params value is missing", conn, req.ID)
+		return
+	}
+
+	var p fastjson.Parser
+	v, err := p.ParseBytes(*req.Params)
+	if err != nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, fmt.Sprintf("failed to parse params: %v", err), conn, req.ID)
+		return
+	}
+
+	subscriptionType := string(v.GetStringBytes("subscription_type"))
+	if subscriptionType == "" {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, subscriptionTypeMissingErrMsg, conn, req.ID)
+		return
+	}
+
+	if subscriptionType != subscriptionTypeSolutions {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, fmt.Sprintf("invalid 'subscription_type' param: '%s', valid values are: [%s]",
+			subscriptionType, subscriptionTypeSolutions), conn, req.ID)
+		return
+	}
+
+	intentID := string(v.GetStringBytes("intent_id"))
+	if intentID == "" {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, intentIDMissingErrMsg, conn, req.ID)
+		return
+	}
+
+	solutions, watcher, ok, stop := h.dApp.WatchIntentSolutions(intentID)
+	if !ok {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidRequest, fmt.Sprintf("intent is not tracked by the relay: %s", intentID), conn, req.ID)
+		return
+	}
+
+	subscriptionID := uuid.New().String()
+
+	h.lock.Lock()
+	h.subscriptions[subscriptionID] = stop
+	h.lock.Unlock()
+
+	response := subscribeResponse{
+		SubscriptionID: subscriptionID,
+	}
+
+	if err = conn.Reply(ctx, req.ID, response); err != nil {
+		logger.Error("error replying to client", "err", err, "reqID", req.ID, "caller", h.remoteAddress)
+		h.stopSubscription(subscriptionID)
+		return
+	}
+
+	logger.Info("dApp subscribed", "subscription_type", subscriptionType, "intent_id", intentID, "caller", h.remoteAddress,
+		"identity", h.identity)
+
+	go h.handleSolutions(conn, subscriptionID, intentID, solutions, watcher)
+}
+
+// handleUnsubscribe handles the unsubscribe method
+func (h *wsDAppConnHandler) handleUnsubscribe(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, "params value is missing", conn, req.ID)
+		return
+	}
+
+	var p fastjson.Parser
+	v, err := p.ParseBytes(*req.Params)
+	if err != nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, fmt.Sprintf("failed to parse params: %v", err), conn, req.ID)
+		return
+	}
+
+	subscriptionID := string(v.GetStringBytes("subscription_id"))
+	if subscriptionID == "" {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, subscriptionIDMissingErrMsg, conn, req.ID)
+		return
+	}
+
+	if !h.stopSubscription(subscriptionID) {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidRequest, fmt.Sprintf("failed to unsubscribe: subscription not found for id: %s", subscriptionID), conn, req.ID)
+		return
+	}
+
+	if err = conn.Reply(ctx, req.ID, "true"); err != nil {
+		logger.Error("error replying to client", "err", err, "reqID", req.ID, "caller", h.remoteAddress)
+		return
+	}
+
+	logger.Info("dApp unsubscribed", "subscriptionID", subscriptionID, "caller", h.remoteAddress, "identity", h.identity)
+}
+
+// parseSolverOperationsQuery parses the intent_id, wait_ms, min_solutions, limit and sort params of getSolverOperations
+func (h *wsDAppConnHandler) parseSolverOperationsQuery(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (*solverOperationsQuery, bool) {
+	if req.Params == nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, "params value is missing", conn, req.ID)
+		return nil, false
+	}
+
+	var p fastjson.Parser
+	v, err := p.ParseBytes(*req.Params)
+	if err != nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, fmt.Sprintf("failed to parse params: %v", err), conn, req.ID)
+		return nil, false
+	}
+
+	intentID := v.GetStringBytes("intent_id")
+	if len(intentID) == 0 {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, intentIDMissingErrMsg, conn, req.ID)
+		return nil, false
+	}
+
+	waitMS, err := intParam(v, "wait_ms", 0)
+	if err != nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, err.Error(), conn, req.ID)
+		return nil, false
+	}
+
+	minSolutions, err := intParam(v, "min_solutions", 1)
+	if err != nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, err.Error(), conn, req.ID)
+		return nil, false
+	}
+
+	limit, err := intParam(v, "limit", 0)
+	if err != nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, err.Error(), conn, req.ID)
+		return nil, false
+	}
+
+	sortBy := string(v.GetStringBytes("sort"))
+	if sortBy != "" && sortBy != sortByBid {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, fmt.Sprintf("unsupported sort value: %s", sortBy), conn, req.ID)
+		return nil, false
+	}
+
+	return &solverOperationsQuery{
+		intentID:     string(intentID),
+		wait:         time.Duration(waitMS) * time.Millisecond,
+		minSolutions: minSolutions,
+		limit:        limit,
+		sortByBid:    sortBy == sortByBid,
+	}, true
+}
+
+// intParam returns the non-negative integer param, defaultValue when it is not set
+func intParam(v *fastjson.Value, name string, defaultValue int) (int, error) {
+	param := v.Get(name)
+	if param == nil {
+		return defaultValue, nil
+	}
+
+	value, err := param.Int()
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+
+	return value, nil
+}
+
+// handleSolutions pushes the solver operations of the intent, and the result of its auction once it closes,
+// to the client until the intent expires, the client unsubscribes or disconnects. A subscriptionStatus notification
+// follows the solver operations the client missed because it did not keep up
+func (h *wsDAppConnHandler) handleSolutions(conn *jsonrpc2.Conn, subscriptionID, intentID string, solutions []types.SolverOperationRaw,
+	watcher *service.SolutionWatcher) {
+	defer h.stopSubscription(subscriptionID)
+
+	ctx := context.Background()
+
+	for i := range solutions {
+		if err := h.notifySolution(ctx, conn, subscriptionID, intentID, solutions[i]); err != nil {
+			logger.Error("error notifying client", "err", err, "caller", h.remoteAddress)
+			return
+		}
+	}
+
+	auctionClosed := h.dApp.AuctionClosed(intentID)
+
+	// reported is the number of dropped solver operations the client was told about
+	var reported uint64
+
+	for {
+		select {
+		case <-conn.DisconnectNotify():
+			return
+		case <-auctionClosed:
+			// the channel stays closed, the result is only pushed once
+			auctionClosed = nil
+
+			auction, ok := h.dApp.Auction(intentID)
+			if !ok {
+				continue
+			}
+
+			err := conn.Notify(ctx, methodSubscribe, auctionNotification{
+				SubscriptionID: subscriptionID,
+				auctionResult:  newAuctionResult(intentID, auction),
+			})
+			if err != nil {
+				logger.Error("error notifying client", "err", err, "caller", h.remoteAddress)
+				return
+			}
+		case solution, ok := <-watcher.Updates:
+			if !ok {
+				return
+			}
+
+			if err := h.notifySolution(ctx, conn, subscriptionID, intentID, solution); err != nil {
+				logger.Error("error notifying client", "err", err, "caller", h.remoteAddress)
+				return
+			}
+		}
+
+		if dropped := watcher.Dropped(); dropped != reported {
+			reported = dropped
+
+			if err := conn.Notify(ctx, methodSubscriptionStatus, watcher.Status(subscriptionID)); err != nil {
+				logger.Error("error notifying client", "err", err, "caller", h.remoteAddress)
+				return
+			}
+		}
+	}
+}
+
+func (h *wsDAppConnHandler) notifySolution(ctx context.Context, conn *jsonrpc2.Conn, subscriptionID, intentID string, solution types.SolverOperationRaw) error {
+	return conn.Notify(ctx, methodSubscribe, solutionNotification{
+		SubscriptionID:  subscriptionID,
+		IntentID:        intentID,
+		SolverOperation: solution,
+	})
+}
+
+// stopSubscription stops the subscription and reports whether it existed
+func (h *wsDAppConnHandler) stopSubscription(subscriptionID string) bool {
+	h.lock.Lock()
+	stop, ok := h.subscriptions[subscriptionID]
+	delete(h.subscriptions, subscriptionID)
+	h.lock.Unlock()
+
+	if ok {
+		stop()
+	}
+
+	return ok
+}
+
+// subscriptionCount returns the number of solutions subscriptions of the connection
+func (h *wsDAppConnHandler) subscriptionCount() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return len(h.subscriptions)
+}
+
+// closeOnDisconnect stops all subscriptions of the connection once the client disconnects
+// and removes it from the live connections
+func (h *wsDAppConnHandler) closeOnDisconnect(conn *jsonrpc2.Conn) {
+	<-conn.DisconnectNotify()
+
+	defer h.subscriptionService.Disconnect(h.connectionID)
+
+	h.lock.Lock()
+	subscriptionIDs := make([]string, 0, len(h.subscriptions))
+	for id := range h.subscriptions {
+		subscriptionIDs = append(subscriptionIDs, id)
+	}
+	h.lock.Unlock()
+
+	for _, id := range subscriptionIDs {
+		h.stopSubscription(id)
+	}
+
+	logger.Info("dApp disconnected", "caller", h.remoteAddress, "identity", h.identity)
+}
+
+// sendErrorMsg formats and sends an RPC error message back to the client
+func (h *wsDAppConnHandler) sendErrorMsg(ctx context.Context, code int, message string, conn *jsonrpc2.Conn, reqID jsonrpc2.ID) {
+	rpcError := &jsonrpc2.Error{
+		Code:    int64(code),
+		Message: message,
+	}
+
+	err := conn.ReplyWithError(ctx, reqID, rpcError)
+	if err != nil {
+		logger.Error("could not respond to client with error message", "err", err, "reqID", reqID, "caller", h.remoteAddress)
+	}
+}
+
+func unmarshalParams(data json.RawMessage, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal params: %v", err)
+	}
+
+	return validateRequest(v)
+}
@@ -0,0 +1,452 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/server/pb"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+)
+
+// grpcMethodPermissions are the permissions required from the callers of the gRPC methods when authentication is enabled
+var grpcMethodPermissions = map[string]config.Permission{
+	pb.Relay_SubmitUserOperation_FullMethodName:   config.PermissionDApp,
+	pb.Relay_GetSolverOperations_FullMethodName:   config.PermissionDApp,
+	pb.Relay_SubscribeIntents_FullMethodName:      config.PermissionSolver,
+	pb.Relay_SubmitSolverOperation_FullMethodName: config.PermissionSolver,
+}
+
+// grpcServer serves the gRPC API of the relay with the services of the HTTP and WebSocket APIs
+type grpcServer struct {
+	pb.UnimplementedRelayServer
+	s *Server
+}
+
+// newGRPCServer returns the gRPC server of the relay API, authenticating and rate limiting the calls like the HTTP API
+func newGRPCServer(s *Server) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx, err := s.authorizeGRPC(ctx, info.FullMethod)
+			if err != nil {
+				return nil, err
+			}
+
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := s.authorizeGRPC(ss.Context(), info.FullMethod)
+			if err != nil {
+				return err
+			}
+
+			return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		}),
+	)
+
+	pb.RegisterRelayServer(server, &grpcServer{s: s})
+
+	return server
+}
+
+// serverStream overrides the context of a server stream with the context carrying the identity of the caller
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorizeGRPC authenticates the caller of a gRPC method from the x-api-key or authorization metadata,
+// checks its permission and its rate limit, and returns the context carrying its identity
+func (s *Server) authorizeGRPC(ctx context.Context, fullMethod string) (context.Context, error) {
+	if s.authenticator != nil {
+		token := grpcToken(ctx)
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, errMissingCredentials.Error())
+		}
+
+		identity, err := s.authenticator.Authenticate(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, errInvalidCredentials.Error())
+		}
+
+		permission := grpcMethodPermissions[fullMethod]
+		if !identity.HasPermission(permission) {
+			return nil, status.Errorf(codes.PermissionDenied, "%s permission is required", permission)
+		}
+
+		ctx = context.WithValue(ctx, identityKey{}, identity)
+	}
+
+	// the methods are rate limited under their short name, for example SubmitUserOperation
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if delay := s.rateLimiter.Reserve(limitGRPC, method, clientKey(identityFromContext(ctx), peerAddress(ctx))); delay > 0 {
+		return nil, status.Error(codes.ResourceExhausted, rateLimitedErrMsg(delay))
+	}
+
+	return ctx, nil
+}
+
+// grpcToken returns the token of the x-api-key metadata or the bearer token of the authorization metadata
+func grpcToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(apiKeyHeader); len(values) != 0 && values[0] != "" {
+		return values[0]
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+// peerAddress returns the remote address of the caller of a gRPC method
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	return p.Addr.String()
+}
+
+// dApp returns the dApp of the name, the dApp of the top level key when the name is empty
+func (g *grpcServer) dApp(ctx context.Context, name string) (*service.DApp, error) {
+	if name == "" {
+		name = config.DefaultDAppName
+	}
+
+	if !dAppAllowed(ctx, name) {
+		return nil, status.Errorf(codes.PermissionDenied, "dApp %s is not allowed", name)
+	}
+
+	dApp, ok := g.s.intentService.DApp(name)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown dApp %s", name)
+	}
+
+	return dApp, nil
+}
+
+// solver returns the solver the caller is bound to
+func (g *grpcServer) solver(ctx context.Context) (*service.Solver, error) {
+	name := solverFromContext(ctx)
+
+	solver, ok := g.s.intentService.Solver(name)
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "solver %s is not configured", name)
+	}
+
+	return solver, nil
+}
+
+func (g *grpcServer) SubmitUserOperation(ctx context.Context, req *pb.SubmitUserOperationRequest) (*pb.SubmitUserOperationReply, error) {
+	dApp, err := g.dApp(ctx, req.GetDapp())
+	if err != nil {
+		return nil, err
+	}
+
+	var userOp types.UserOperationWithHintsRaw
+	err = json.Unmarshal(req.GetUserOperation(), &userOp)
+	if err == nil {
+		err = validateRequest(&userOp)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user operation: %v", err)
+	}
+
+	chainID, op, hints := userOp.Decode()
+	intentID, err := dApp.SubmitUserOperation(ctx, chainID, op, hints)
+	if err != nil {
+		logger.Error("failed to submit user operation", "error", err)
+		if errors.Is(err, service.ErrInvalidUserOperation) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, "failed to submit user operation")
+	}
+
+	return &pb.SubmitUserOperationReply{IntentId: intentID}, nil
+}
+
+func (g *grpcServer) GetSolverOperations(ctx context.Context, req *pb.GetSolverOperationsRequest) (*pb.GetSolverOperationsReply, error) {
+	dApp, err := g.dApp(ctx, req.GetDapp())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetIntentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "intent_id is required")
+	}
+
+	minSolutions := int(req.GetMinSolutions())
+	if minSolutions == 0 {
+		minSolutions = 1
+	}
+
+	solverOps, err := querySolverOperations(ctx, dApp, &solverOperationsQuery{
+		intentID:     req.GetIntentId(),
+		wait:         time.Duration(req.GetWaitMs()) * time.Millisecond,
+		minSolutions: minSolutions,
+		limit:        int(req.GetLimit()),
+		sortByBid:    req.GetSortByBid(),
+	})
+	if err != nil {
+		logger.Error("failed to get intent solutions", "error", err)
+		return nil, status.Error(codes.Internal, "failed to get solver operations")
+	}
+
+	reply := &pb.GetSolverOperationsReply{
+		SolverOperations: make([][]byte, 0, len(solverOps)),
+	}
+
+	for j := range solverOps {
+		b, err := json.Marshal(&solverOps[j])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal solver operation: %v", err)
+		}
+
+		reply.SolverOperations = append(reply.SolverOperations, b)
+	}
+
+	return reply, nil
+}
+
+func (g *grpcServer) SubscribeIntents(req *pb.SubscribeIntentsRequest, stream pb.Relay_SubscribeIntentsServer) error {
+	ctx := stream.Context()
+
+	solver, err := g.solver(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter, err := intentFilter(req.GetFilter())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
+	}
+	filter.Solver = solver.Name()
+
+	remoteAddress := peerAddress(ctx)
+
+	// evicted is closed when the relay evicts the client, the stream is then ended
+	evicted := make(chan struct{})
+	evict := sync.OnceFunc(func() { close(evicted) })
+
+	// each stream is a connection of its own, its subscription ends with it
+	connectionID := g.s.subscriptionService.Connect(service.ConnectionInfo{
+		Kind:          service.ConnectionKindGRPCStream,
+		RemoteAddress: remoteAddress,
+		Identity:      identityFromContext(ctx),
+		Solver:        solver.Name(),
+	}, evict)
+	defer g.s.subscriptionService.Disconnect(connectionID)
+
+	subscription, err := g.s.subscriptionService.Subscribe(connectionID, service.SubscriptionTypeIntent, filter,
+		req.GetFromEpoch(), req.GetFromSequence())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to subscribe: %v", err)
+	}
+
+	// the headers tell the client the subscription is registered
+	header := metadata.MD{}
+	if req.GetFromSequence() != 0 {
+		header.Set("replayed", strconv.Itoa(subscription.Replayed))
+		header.Set("replay-truncated", strconv.FormatBool(subscription.ReplayTruncated))
+	}
+
+	err = stream.SendHeader(header)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("gRPC client subscribed to intents", "caller", remoteAddress, "identity", identityFromContext(ctx),
+		"solver", solver.Name(), "from_epoch", req.GetFromEpoch(), "from_sequence", req.GetFromSequence(), "replayed", subscription.Replayed)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("gRPC client disconnected", "caller", remoteAddress, "identity", identityFromContext(ctx))
+			return nil
+		case <-evicted:
+			return status.Error(codes.ResourceExhausted, "evicted for not keeping up with the notifications")
+		case msg, ok := <-subscription.NotificationChannel:
+			if !ok {
+				return nil
+			}
+
+			notification := intentNotification(msg)
+			if notification == nil {
+				continue
+			}
+
+			err = stream.Send(notification)
+			if err != nil {
+				logger.Error("error sending intent to gRPC client", "err", err, "caller", remoteAddress)
+				return err
+			}
+
+			subscription.Delivered()
+		}
+	}
+}
+
+// intentNotification returns the gRPC notification of an intent or of a subscription status, nil for other notifications
+func intentNotification(msg interface{}) *pb.IntentNotification {
+	switch msg := msg.(type) {
+	case *service.IntentNotification:
+		return &pb.IntentNotification{
+			IntentId:      msg.IntentID,
+			DappAddress:   msg.DappAddress,
+			SenderAddress: msg.SenderAddress,
+			Intent:        msg.Intent,
+			Timestamp:     msg.Timestamp,
+			Sequence:      msg.Sequence,
+			Epoch:         msg.Epoch,
+		}
+	case *service.SubscriptionStatus:
+		return &pb.IntentNotification{
+			Status: &pb.SubscriptionStatus{
+				SubscriptionId:     msg.SubscriptionID,
+				Pending:            uint32(msg.Pending),
+				BufferSize:         uint32(msg.BufferSize),
+				Dropped:            msg.Dropped,
+				SlowConsumerPolicy: string(msg.SlowConsumerPolicy),
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+func (g *grpcServer) SubmitSolverOperation(ctx context.Context, req *pb.SubmitSolverOperationRequest) (*pb.SubmitSolverOperationReply, error) {
+	solver, err := g.solver(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetIntentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, intentIDMissingErrMsg)
+	}
+
+	solverOperation, hash, err := g.s.intentService.DecodeSolverOperation(req.GetSolverOperation())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	logger.Debug("gRPC client submitted solver operation", "intent_id", req.GetIntentId(), "from", solverOperation.From,
+		"solver_operation_hash", hash, "caller", peerAddress(ctx), "identity", identityFromContext(ctx), "solver", solver.Name())
+
+	submitted, err := solver.SubmitIntentSolution(context.Background(), req.GetIntentId(), req.GetSolverOperation())
+	if err != nil {
+		return nil, status.Errorf(submitErrorGRPCCode(err), "failed to submit solver operation: %v", err)
+	}
+
+	return &pb.SubmitSolverOperationReply{
+		SolverOperationHash: hash.Hex(),
+		SolutionId:          submitted.SolutionID,
+		AcceptedAt:          timestamppb.New(submitted.AcceptedAt),
+	}, nil
+}
+
+// submitErrorGRPCCode returns the gRPC status code of an error submitting a solver operation,
+// the counterpart of the JSON-RPC error code of the WebSocket API
+func submitErrorGRPCCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, service.ErrUnknownIntent):
+		return codes.NotFound
+	case errors.Is(err, service.ErrIntentExpired):
+		return codes.FailedPrecondition
+	case errors.Is(err, service.ErrBDNRejected):
+		return codes.Aborted
+	default:
+		return codes.Internal
+	}
+}
+
+// intentFilter returns the subscription filter of the intent filter of a gRPC subscription
+func intentFilter(f *pb.IntentFilter) (service.SubscriptionFilter, error) {
+	filter := service.SubscriptionFilter{
+		ChainID:     f.GetChainId(),
+		MinDeadline: f.GetMinDeadline(),
+	}
+
+	addresses := []struct {
+		name  string
+		value string
+		dest  *common.Address
+	}{
+		{"dapp_address", f.GetDappAddress(), &filter.DAppAddress},
+		{"sender_address", f.GetSenderAddress(), &filter.SenderAddress},
+		{"to", f.GetTo(), &filter.To},
+		{"control", f.GetControl(), &filter.Control},
+	}
+
+	for _, address := range addresses {
+		if address.value == "" {
+			continue
+		}
+
+		if !common.IsHexAddress(address.value) {
+			return filter, fmt.Errorf("%s must be a hex address", address.name)
+		}
+
+		*address.dest = common.HexToAddress(address.value)
+	}
+
+	return filter, nil
+}
+
+// listenGRPC listens on the gRPC port of the config
+func (s *Server) listenGRPC() (net.Listener, error) {
+	address := fmt.Sprintf(":%v", s.cfg.GRPCPort)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on gRPC address %s: %v", address, err)
+	}
+
+	return listener, nil
+}
+
+// serveGRPC serves the gRPC API until the server is stopped
+func (s *Server) serveGRPC(listener net.Listener) error {
+	logger.Info("starting gRPC server", "address", listener.Addr().String())
+
+	err := s.grpcServer.Serve(listener)
+	if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("failed to start gRPC server: %v", err)
+	}
+
+	return nil
+}
@@ -1,9 +1,73 @@
 package server
 
+import (
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/store"
+)
+
 type pingResponse struct {
-	Pong string `json:"pong"`
+	Pong               string `json:"pong"`
+	BDNConnectionState string `json:"bdn_connection_state"`
 }
 
 type subscribeResponse struct {
 	SubscriptionID string `json:"subscription_id"`
+	// Replayed and ReplayTruncated are set on the subscriptions resuming from a sequence number
+	Replayed        *int  `json:"replayed,omitempty"`
+	ReplayTruncated *bool `json:"replay_truncated,omitempty"`
+}
+
+type submitUserOperationResponse struct {
+	IntentID string `json:"intent_id"`
+}
+
+// submitSolverOperationResponse acknowledges a solver operation the BDN accepted
+type submitSolverOperationResponse struct {
+	SolutionID          string      `json:"solution_id"`
+	AcceptedAt          time.Time   `json:"accepted_at"`
+	SolverOperationHash common.Hash `json:"solver_operation_hash"`
+}
+
+type solutionNotification struct {
+	SubscriptionID  string                   `json:"subscription_id"`
+	IntentID        string                   `json:"intent_id"`
+	SolverOperation types.SolverOperationRaw `json:"solver_operation"`
+}
+
+// auctionResult is the result of the auction of an intent pushed to the dApp once the auction closes
+type auctionResult struct {
+	IntentID         string                     `json:"intent_id"`
+	ClosedAt         time.Time                  `json:"closed_at"`
+	SolverOperations []types.SolverOperationRaw `json:"solver_operations"`
+}
+
+func newAuctionResult(intentID string, auction *store.Auction) *auctionResult {
+	return &auctionResult{
+		IntentID:         intentID,
+		ClosedAt:         auction.ClosedAt,
+		SolverOperations: auction.SolverOperations,
+	}
+}
+
+type auctionNotification struct {
+	SubscriptionID string `json:"subscription_id"`
+	*auctionResult
+}
+
+// droppedEvent tells a streaming client how many solver operations of the intent it missed since the stream started
+type droppedEvent struct {
+	IntentID string `json:"intent_id"`
+	Dropped  uint64 `json:"dropped"`
+}
+
+type bundleOperationsRequest struct {
+	IntentID              string         `json:"intent_id" validate:"required"`
+	SolverOperationHashes []common.Hash  `json:"solver_operation_hashes"`
+	Bundler               common.Address `json:"bundler"`
+	Nonce                 *hexutil.Big   `json:"nonce"`
 }
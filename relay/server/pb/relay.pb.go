@@ -0,0 +1,1055 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.2
+// source: relay/server/pb/relay.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubmitUserOperationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// dapp is the name of the dApp submitting the user operation, the dApp of the top level key when empty
+	Dapp string `protobuf:"bytes,1,opt,name=dapp,proto3" json:"dapp,omitempty"`
+	// user_operation is the JSON of the user operation with its hints, as posted to /userOperation
+	UserOperation []byte `protobuf:"bytes,2,opt,name=user_operation,json=userOperation,proto3" json:"user_operation,omitempty"`
+}
+
+func (x *SubmitUserOperationRequest) Reset() {
+	*x = SubmitUserOperationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitUserOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitUserOperationRequest) ProtoMessage() {}
+
+func (x *SubmitUserOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitUserOperationRequest.ProtoReflect.Descriptor instead.
+func (*SubmitUserOperationRequest) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubmitUserOperationRequest) GetDapp() string {
+	if x != nil {
+		return x.Dapp
+	}
+	return ""
+}
+
+func (x *SubmitUserOperationRequest) GetUserOperation() []byte {
+	if x != nil {
+		return x.UserOperation
+	}
+	return nil
+}
+
+type SubmitUserOperationReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IntentId string `protobuf:"bytes,1,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+}
+
+func (x *SubmitUserOperationReply) Reset() {
+	*x = SubmitUserOperationReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitUserOperationReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitUserOperationReply) ProtoMessage() {}
+
+func (x *SubmitUserOperationReply) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitUserOperationReply.ProtoReflect.Descriptor instead.
+func (*SubmitUserOperationReply) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitUserOperationReply) GetIntentId() string {
+	if x != nil {
+		return x.IntentId
+	}
+	return ""
+}
+
+type GetSolverOperationsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// dapp is the name of the dApp which submitted the intent, the dApp of the top level key when empty
+	Dapp     string `protobuf:"bytes,1,opt,name=dapp,proto3" json:"dapp,omitempty"`
+	IntentId string `protobuf:"bytes,2,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	// wait_ms waits up to this long for min_solutions solver operations, the operations received so far are returned when 0
+	WaitMs uint32 `protobuf:"varint,3,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
+	// min_solutions is the number of solver operations to wait for, 1 when 0
+	MinSolutions uint32 `protobuf:"varint,4,opt,name=min_solutions,json=minSolutions,proto3" json:"min_solutions,omitempty"`
+	// limit caps the number of solver operations returned, all of them are returned when 0
+	Limit uint32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// sort_by_bid ranks the solver operations by bid amount, highest first
+	SortByBid bool `protobuf:"varint,6,opt,name=sort_by_bid,json=sortByBid,proto3" json:"sort_by_bid,omitempty"`
+}
+
+func (x *GetSolverOperationsRequest) Reset() {
+	*x = GetSolverOperationsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSolverOperationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSolverOperationsRequest) ProtoMessage() {}
+
+func (x *GetSolverOperationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSolverOperationsRequest.ProtoReflect.Descriptor instead.
+func (*GetSolverOperationsRequest) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetSolverOperationsRequest) GetDapp() string {
+	if x != nil {
+		return x.Dapp
+	}
+	return ""
+}
+
+func (x *GetSolverOperationsRequest) GetIntentId() string {
+	if x != nil {
+		return x.IntentId
+	}
+	return ""
+}
+
+func (x *GetSolverOperationsRequest) GetWaitMs() uint32 {
+	if x != nil {
+		return x.WaitMs
+	}
+	return 0
+}
+
+func (x *GetSolverOperationsRequest) GetMinSolutions() uint32 {
+	if x != nil {
+		return x.MinSolutions
+	}
+	return 0
+}
+
+func (x *GetSolverOperationsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetSolverOperationsRequest) GetSortByBid() bool {
+	if x != nil {
+		return x.SortByBid
+	}
+	return false
+}
+
+type GetSolverOperationsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// solver_operations are the JSON of the solver operations with their simulation results, as returned by /solverOperations
+	SolverOperations [][]byte `protobuf:"bytes,1,rep,name=solver_operations,json=solverOperations,proto3" json:"solver_operations,omitempty"`
+}
+
+func (x *GetSolverOperationsReply) Reset() {
+	*x = GetSolverOperationsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSolverOperationsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSolverOperationsReply) ProtoMessage() {}
+
+func (x *GetSolverOperationsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSolverOperationsReply.ProtoReflect.Descriptor instead.
+func (*GetSolverOperationsReply) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSolverOperationsReply) GetSolverOperations() [][]byte {
+	if x != nil {
+		return x.SolverOperations
+	}
+	return nil
+}
+
+// IntentFilter narrows the intents of a subscription, empty fields match everything
+type IntentFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DappAddress   string `protobuf:"bytes,1,opt,name=dapp_address,json=dappAddress,proto3" json:"dapp_address,omitempty"`
+	SenderAddress string `protobuf:"bytes,2,opt,name=sender_address,json=senderAddress,proto3" json:"sender_address,omitempty"`
+	ChainId       uint64 `protobuf:"varint,3,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	To            string `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Control       string `protobuf:"bytes,5,opt,name=control,proto3" json:"control,omitempty"`
+	// min_deadline skips intents with fewer blocks left before their deadline, it requires the Atlas chain node
+	MinDeadline uint64 `protobuf:"varint,6,opt,name=min_deadline,json=minDeadline,proto3" json:"min_deadline,omitempty"`
+}
+
+func (x *IntentFilter) Reset() {
+	*x = IntentFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentFilter) ProtoMessage() {}
+
+func (x *IntentFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentFilter.ProtoReflect.Descriptor instead.
+func (*IntentFilter) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *IntentFilter) GetDappAddress() string {
+	if x != nil {
+		return x.DappAddress
+	}
+	return ""
+}
+
+func (x *IntentFilter) GetSenderAddress() string {
+	if x != nil {
+		return x.SenderAddress
+	}
+	return ""
+}
+
+func (x *IntentFilter) GetChainId() uint64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *IntentFilter) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *IntentFilter) GetControl() string {
+	if x != nil {
+		return x.Control
+	}
+	return ""
+}
+
+func (x *IntentFilter) GetMinDeadline() uint64 {
+	if x != nil {
+		return x.MinDeadline
+	}
+	return 0
+}
+
+type SubscribeIntentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *IntentFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// from_sequence replays the kept intents from this sequence number on before the live ones, 0 starts with the live ones
+	FromSequence uint64 `protobuf:"varint,2,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"`
+	// from_epoch is the epoch of from_sequence, the replay is truncated when it is not the current epoch of the relay
+	FromEpoch string `protobuf:"bytes,3,opt,name=from_epoch,json=fromEpoch,proto3" json:"from_epoch,omitempty"`
+}
+
+func (x *SubscribeIntentsRequest) Reset() {
+	*x = SubscribeIntentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeIntentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeIntentsRequest) ProtoMessage() {}
+
+func (x *SubscribeIntentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeIntentsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeIntentsRequest) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SubscribeIntentsRequest) GetFilter() *IntentFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *SubscribeIntentsRequest) GetFromSequence() uint64 {
+	if x != nil {
+		return x.FromSequence
+	}
+	return 0
+}
+
+func (x *SubscribeIntentsRequest) GetFromEpoch() string {
+	if x != nil {
+		return x.FromEpoch
+	}
+	return ""
+}
+
+type IntentNotification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IntentId      string `protobuf:"bytes,1,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	DappAddress   string `protobuf:"bytes,2,opt,name=dapp_address,json=dappAddress,proto3" json:"dapp_address,omitempty"`
+	SenderAddress string `protobuf:"bytes,3,opt,name=sender_address,json=senderAddress,proto3" json:"sender_address,omitempty"`
+	// intent is the JSON of the partial user operation of the intent
+	Intent    []byte `protobuf:"bytes,4,opt,name=intent,proto3" json:"intent,omitempty"`
+	Timestamp string `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// status is set, instead of the intent fields, on the periodic status notifications of the subscription
+	Status *SubscriptionStatus `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	// sequence increases by one with each intent the relay receives, across all the solvers
+	Sequence uint64 `protobuf:"varint,7,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// epoch identifies the run of the relay which numbered the intent, the sequences restart with every run
+	Epoch string `protobuf:"bytes,8,opt,name=epoch,proto3" json:"epoch,omitempty"`
+}
+
+func (x *IntentNotification) Reset() {
+	*x = IntentNotification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntentNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentNotification) ProtoMessage() {}
+
+func (x *IntentNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentNotification.ProtoReflect.Descriptor instead.
+func (*IntentNotification) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *IntentNotification) GetIntentId() string {
+	if x != nil {
+		return x.IntentId
+	}
+	return ""
+}
+
+func (x *IntentNotification) GetDappAddress() string {
+	if x != nil {
+		return x.DappAddress
+	}
+	return ""
+}
+
+func (x *IntentNotification) GetSenderAddress() string {
+	if x != nil {
+		return x.SenderAddress
+	}
+	return ""
+}
+
+func (x *IntentNotification) GetIntent() []byte {
+	if x != nil {
+		return x.Intent
+	}
+	return nil
+}
+
+func (x *IntentNotification) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *IntentNotification) GetStatus() *SubscriptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *IntentNotification) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *IntentNotification) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+type SubscriptionStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriptionId string `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	// pending is the number of notifications buffered for the client
+	Pending    uint32 `protobuf:"varint,2,opt,name=pending,proto3" json:"pending,omitempty"`
+	BufferSize uint32 `protobuf:"varint,3,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	// dropped is the number of notifications dropped since the subscription started
+	Dropped            uint64 `protobuf:"varint,4,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	SlowConsumerPolicy string `protobuf:"bytes,5,opt,name=slow_consumer_policy,json=slowConsumerPolicy,proto3" json:"slow_consumer_policy,omitempty"`
+}
+
+func (x *SubscriptionStatus) Reset() {
+	*x = SubscriptionStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscriptionStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionStatus) ProtoMessage() {}
+
+func (x *SubscriptionStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionStatus.ProtoReflect.Descriptor instead.
+func (*SubscriptionStatus) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SubscriptionStatus) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *SubscriptionStatus) GetPending() uint32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *SubscriptionStatus) GetBufferSize() uint32 {
+	if x != nil {
+		return x.BufferSize
+	}
+	return 0
+}
+
+func (x *SubscriptionStatus) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *SubscriptionStatus) GetSlowConsumerPolicy() string {
+	if x != nil {
+		return x.SlowConsumerPolicy
+	}
+	return ""
+}
+
+type SubmitSolverOperationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IntentId string `protobuf:"bytes,1,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	// solver_operation is the JSON of the solver operation, as sent in the intent_solution param of submitSolverOperation
+	SolverOperation []byte `protobuf:"bytes,2,opt,name=solver_operation,json=solverOperation,proto3" json:"solver_operation,omitempty"`
+}
+
+func (x *SubmitSolverOperationRequest) Reset() {
+	*x = SubmitSolverOperationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitSolverOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitSolverOperationRequest) ProtoMessage() {}
+
+func (x *SubmitSolverOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitSolverOperationRequest.ProtoReflect.Descriptor instead.
+func (*SubmitSolverOperationRequest) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubmitSolverOperationRequest) GetIntentId() string {
+	if x != nil {
+		return x.IntentId
+	}
+	return ""
+}
+
+func (x *SubmitSolverOperationRequest) GetSolverOperation() []byte {
+	if x != nil {
+		return x.SolverOperation
+	}
+	return nil
+}
+
+type SubmitSolverOperationReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// solver_operation_hash is the EIP-712 hash of the solver operation
+	SolverOperationHash string `protobuf:"bytes,1,opt,name=solver_operation_hash,json=solverOperationHash,proto3" json:"solver_operation_hash,omitempty"`
+	// solution_id is the id the BDN assigned to the solution
+	SolutionId string `protobuf:"bytes,2,opt,name=solution_id,json=solutionId,proto3" json:"solution_id,omitempty"`
+	// accepted_at is when the relay received the acknowledgement of the BDN
+	AcceptedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=accepted_at,json=acceptedAt,proto3" json:"accepted_at,omitempty"`
+}
+
+func (x *SubmitSolverOperationReply) Reset() {
+	*x = SubmitSolverOperationReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_server_pb_relay_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitSolverOperationReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitSolverOperationReply) ProtoMessage() {}
+
+func (x *SubmitSolverOperationReply) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_server_pb_relay_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitSolverOperationReply.ProtoReflect.Descriptor instead.
+func (*SubmitSolverOperationReply) Descriptor() ([]byte, []int) {
+	return file_relay_server_pb_relay_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SubmitSolverOperationReply) GetSolverOperationHash() string {
+	if x != nil {
+		return x.SolverOperationHash
+	}
+	return ""
+}
+
+func (x *SubmitSolverOperationReply) GetSolutionId() string {
+	if x != nil {
+		return x.SolutionId
+	}
+	return ""
+}
+
+func (x *SubmitSolverOperationReply) GetAcceptedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AcceptedAt
+	}
+	return nil
+}
+
+var File_relay_server_pb_relay_proto protoreflect.FileDescriptor
+
+var file_relay_server_pb_relay_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x70,
+	0x62, 0x2f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x72,
+	0x65, 0x6c, 0x61, 0x79, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x57, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x55,
+	0x73, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x70, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x64, 0x61, 0x70, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x5f,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0d, 0x75, 0x73, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x37,
+	0x0a, 0x18, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x55, 0x73, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xc1, 0x01, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x53,
+	0x6f, 0x6c, 0x76, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x70, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x70, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x77, 0x61, 0x69, 0x74, 0x5f,
+	0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x77, 0x61, 0x69, 0x74, 0x4d, 0x73,
+	0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x53, 0x6f, 0x6c, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0b, 0x73,
+	0x6f, 0x72, 0x74, 0x5f, 0x62, 0x79, 0x5f, 0x62, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x73, 0x6f, 0x72, 0x74, 0x42, 0x79, 0x42, 0x69, 0x64, 0x22, 0x47, 0x0a, 0x18, 0x47,
+	0x65, 0x74, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x11, 0x73, 0x6f, 0x6c, 0x76, 0x65,
+	0x72, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x10, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x0c, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x46,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x61, 0x70, 0x70, 0x5f, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x61, 0x70,
+	0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x19, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x69, 0x6e, 0x5f, 0x64, 0x65, 0x61, 0x64,
+	0x6c, 0x69, 0x6e, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x44,
+	0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x8a, 0x01, 0x0a, 0x17, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x49, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x12, 0x23, 0x0a, 0x0d, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x66, 0x72, 0x6f, 0x6d, 0x53, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x65, 0x70,
+	0x6f, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x6f, 0x6d, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x22, 0x96, 0x02, 0x0a, 0x12, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x61, 0x70, 0x70,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x61, 0x70, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x06, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79,
+	0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x22, 0xc4, 0x01,
+	0x0a, 0x12, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
+	0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x75, 0x66, 0x66, 0x65,
+	0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x75,
+	0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x72, 0x6f, 0x70,
+	0x70, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x64, 0x72, 0x6f, 0x70, 0x70,
+	0x65, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x6c, 0x6f, 0x77, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x75,
+	0x6d, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x12, 0x73, 0x6c, 0x6f, 0x77, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x22, 0x66, 0x0a, 0x1c, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x6f,
+	0x6c, 0x76, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x49,
+	0x64, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x5f, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x73, 0x6f, 0x6c,
+	0x76, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xae, 0x01, 0x0a,
+	0x1a, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x32, 0x0a, 0x15, 0x73,
+	0x6f, 0x6c, 0x76, 0x65, 0x72, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x73, 0x6f, 0x6c, 0x76,
+	0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x1f, 0x0a, 0x0b, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x12, 0x3b, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x41, 0x74, 0x32, 0xf7, 0x02,
+	0x0a, 0x05, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x5b, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21,
+	0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1f, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x6c, 0x76, 0x65,
+	0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x21, 0x2e, 0x72, 0x65,
+	0x6c, 0x61, 0x79, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x72,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x51, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x49, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1e, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x49, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x00, 0x30, 0x01, 0x12, 0x61, 0x0a, 0x15, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x6f,
+	0x6c, 0x76, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x2e,
+	0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x6f, 0x6c, 0x76,
+	0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x21, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x6c, 0x6f, 0x58, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x2d,
+	0x4c, 0x61, 0x62, 0x73, 0x2f, 0x62, 0x64, 0x6e, 0x2d, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2d, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2f,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_relay_server_pb_relay_proto_rawDescOnce sync.Once
+	file_relay_server_pb_relay_proto_rawDescData = file_relay_server_pb_relay_proto_rawDesc
+)
+
+func file_relay_server_pb_relay_proto_rawDescGZIP() []byte {
+	file_relay_server_pb_relay_proto_rawDescOnce.Do(func() {
+		file_relay_server_pb_relay_proto_rawDescData = protoimpl.X.CompressGZIP(file_relay_server_pb_relay_proto_rawDescData)
+	})
+	return file_relay_server_pb_relay_proto_rawDescData
+}
+
+var file_relay_server_pb_relay_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_relay_server_pb_relay_proto_goTypes = []any{
+	(*SubmitUserOperationRequest)(nil),   // 0: relay.SubmitUserOperationRequest
+	(*SubmitUserOperationReply)(nil),     // 1: relay.SubmitUserOperationReply
+	(*GetSolverOperationsRequest)(nil),   // 2: relay.GetSolverOperationsRequest
+	(*GetSolverOperationsReply)(nil),     // 3: relay.GetSolverOperationsReply
+	(*IntentFilter)(nil),                 // 4: relay.IntentFilter
+	(*SubscribeIntentsRequest)(nil),      // 5: relay.SubscribeIntentsRequest
+	(*IntentNotification)(nil),           // 6: relay.IntentNotification
+	(*SubscriptionStatus)(nil),           // 7: relay.SubscriptionStatus
+	(*SubmitSolverOperationRequest)(nil), // 8: relay.SubmitSolverOperationRequest
+	(*SubmitSolverOperationReply)(nil),   // 9: relay.SubmitSolverOperationReply
+	(*timestamppb.Timestamp)(nil),        // 10: google.protobuf.Timestamp
+}
+var file_relay_server_pb_relay_proto_depIdxs = []int32{
+	4,  // 0: relay.SubscribeIntentsRequest.filter:type_name -> relay.IntentFilter
+	7,  // 1: relay.IntentNotification.status:type_name -> relay.SubscriptionStatus
+	10, // 2: relay.SubmitSolverOperationReply.accepted_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: relay.Relay.SubmitUserOperation:input_type -> relay.SubmitUserOperationRequest
+	2,  // 4: relay.Relay.GetSolverOperations:input_type -> relay.GetSolverOperationsRequest
+	5,  // 5: relay.Relay.SubscribeIntents:input_type -> relay.SubscribeIntentsRequest
+	8,  // 6: relay.Relay.SubmitSolverOperation:input_type -> relay.SubmitSolverOperationRequest
+	1,  // 7: relay.Relay.SubmitUserOperation:output_type -> relay.SubmitUserOperationReply
+	3,  // 8: relay.Relay.GetSolverOperations:output_type -> relay.GetSolverOperationsReply
+	6,  // 9: relay.Relay.SubscribeIntents:output_type -> relay.IntentNotification
+	9,  // 10: relay.Relay.SubmitSolverOperation:output_type -> relay.SubmitSolverOperationReply
+	7,  // [7:11] is the sub-list for method output_type
+	3,  // [3:7] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_relay_server_pb_relay_proto_init() }
+func file_relay_server_pb_relay_proto_init() {
+	if File_relay_server_pb_relay_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_relay_server_pb_relay_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SubmitUserOperationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SubmitUserOperationReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetSolverOperationsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetSolverOperationsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*IntentFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*SubscribeIntentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*IntentNotification); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*SubscriptionStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*SubmitSolverOperationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_server_pb_relay_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*SubmitSolverOperationReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_relay_server_pb_relay_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_relay_server_pb_relay_proto_goTypes,
+		DependencyIndexes: file_relay_server_pb_relay_proto_depIdxs,
+		MessageInfos:      file_relay_server_pb_relay_proto_msgTypes,
+	}.Build()
+	File_relay_server_pb_relay_proto = out.File
+	file_relay_server_pb_relay_proto_rawDesc = nil
+	file_relay_server_pb_relay_proto_goTypes = nil
+	file_relay_server_pb_relay_proto_depIdxs = nil
+}
@@ -0,0 +1,268 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.27.2
+// source: relay/server/pb/relay.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Relay_SubmitUserOperation_FullMethodName   = "/relay.Relay/SubmitUserOperation"
+	Relay_GetSolverOperations_FullMethodName   = "/relay.Relay/GetSolverOperations"
+	Relay_SubscribeIntents_FullMethodName      = "/relay.Relay/SubscribeIntents"
+	Relay_SubmitSolverOperation_FullMethodName = "/relay.Relay/SubmitSolverOperation"
+)
+
+// RelayClient is the client API for Relay service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Relay is the gRPC API of the relay, served alongside the HTTP and WebSocket APIs.
+// The Atlas operations are carried as their JSON encoding, the same as on the HTTP and WebSocket APIs
+type RelayClient interface {
+	// SubmitUserOperation submits the user operation of a dApp to the BDN as an intent
+	SubmitUserOperation(ctx context.Context, in *SubmitUserOperationRequest, opts ...grpc.CallOption) (*SubmitUserOperationReply, error)
+	// GetSolverOperations returns the solver operations received for an intent submitted through the relay
+	GetSolverOperations(ctx context.Context, in *GetSolverOperationsRequest, opts ...grpc.CallOption) (*GetSolverOperationsReply, error)
+	// SubscribeIntents streams the intents received by the solver of the caller. A subscription resuming from a
+	// sequence number sends its replayed and replay-truncated response headers once the missed intents are queued
+	SubscribeIntents(ctx context.Context, in *SubscribeIntentsRequest, opts ...grpc.CallOption) (Relay_SubscribeIntentsClient, error)
+	// SubmitSolverOperation submits a solver operation for an intent to the BDN under the key of the solver of the caller
+	SubmitSolverOperation(ctx context.Context, in *SubmitSolverOperationRequest, opts ...grpc.CallOption) (*SubmitSolverOperationReply, error)
+}
+
+type relayClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRelayClient(cc grpc.ClientConnInterface) RelayClient {
+	return &relayClient{cc}
+}
+
+func (c *relayClient) SubmitUserOperation(ctx context.Context, in *SubmitUserOperationRequest, opts ...grpc.CallOption) (*SubmitUserOperationReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitUserOperationReply)
+	err := c.cc.Invoke(ctx, Relay_SubmitUserOperation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayClient) GetSolverOperations(ctx context.Context, in *GetSolverOperationsRequest, opts ...grpc.CallOption) (*GetSolverOperationsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSolverOperationsReply)
+	err := c.cc.Invoke(ctx, Relay_GetSolverOperations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayClient) SubscribeIntents(ctx context.Context, in *SubscribeIntentsRequest, opts ...grpc.CallOption) (Relay_SubscribeIntentsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Relay_ServiceDesc.Streams[0], Relay_SubscribeIntents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &relaySubscribeIntentsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Relay_SubscribeIntentsClient interface {
+	Recv() (*IntentNotification, error)
+	grpc.ClientStream
+}
+
+type relaySubscribeIntentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *relaySubscribeIntentsClient) Recv() (*IntentNotification, error) {
+	m := new(IntentNotification)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *relayClient) SubmitSolverOperation(ctx context.Context, in *SubmitSolverOperationRequest, opts ...grpc.CallOption) (*SubmitSolverOperationReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitSolverOperationReply)
+	err := c.cc.Invoke(ctx, Relay_SubmitSolverOperation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RelayServer is the server API for Relay service.
+// All implementations must embed UnimplementedRelayServer
+// for forward compatibility
+//
+// Relay is the gRPC API of the relay, served alongside the HTTP and WebSocket APIs.
+// The Atlas operations are carried as their JSON encoding, the same as on the HTTP and WebSocket APIs
+type RelayServer interface {
+	// SubmitUserOperation submits the user operation of a dApp to the BDN as an intent
+	SubmitUserOperation(context.Context, *SubmitUserOperationRequest) (*SubmitUserOperationReply, error)
+	// GetSolverOperations returns the solver operations received for an intent submitted through the relay
+	GetSolverOperations(context.Context, *GetSolverOperationsRequest) (*GetSolverOperationsReply, error)
+	// SubscribeIntents streams the intents received by the solver of the caller. A subscription resuming from a
+	// sequence number sends its replayed and replay-truncated response headers once the missed intents are queued
+	SubscribeIntents(*SubscribeIntentsRequest, Relay_SubscribeIntentsServer) error
+	// SubmitSolverOperation submits a solver operation for an intent to the BDN under the key of the solver of the caller
+	SubmitSolverOperation(context.Context, *SubmitSolverOperationRequest) (*SubmitSolverOperationReply, error)
+	mustEmbedUnimplementedRelayServer()
+}
+
+// UnimplementedRelayServer must be embedded to have forward compatible implementations.
+type UnimplementedRelayServer struct {
+}
+
+func (UnimplementedRelayServer) SubmitUserOperation(context.Context, *SubmitUserOperationRequest) (*SubmitUserOperationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitUserOperation not implemented")
+}
+func (UnimplementedRelayServer) GetSolverOperations(context.Context, *GetSolverOperationsRequest) (*GetSolverOperationsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSolverOperations not implemented")
+}
+func (UnimplementedRelayServer) SubscribeIntents(*SubscribeIntentsRequest, Relay_SubscribeIntentsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeIntents not implemented")
+}
+func (UnimplementedRelayServer) SubmitSolverOperation(context.Context, *SubmitSolverOperationRequest) (*SubmitSolverOperationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitSolverOperation not implemented")
+}
+func (UnimplementedRelayServer) mustEmbedUnimplementedRelayServer() {}
+
+// UnsafeRelayServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RelayServer will
+// result in compilation errors.
+type UnsafeRelayServer interface {
+	mustEmbedUnimplementedRelayServer()
+}
+
+func RegisterRelayServer(s grpc.ServiceRegistrar, srv RelayServer) {
+	s.RegisterService(&Relay_ServiceDesc, srv)
+}
+
+func _Relay_SubmitUserOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitUserOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayServer).SubmitUserOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Relay_SubmitUserOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayServer).SubmitUserOperation(ctx, req.(*SubmitUserOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Relay_GetSolverOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSolverOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayServer).GetSolverOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Relay_GetSolverOperations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayServer).GetSolverOperations(ctx, req.(*GetSolverOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Relay_SubscribeIntents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeIntentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RelayServer).SubscribeIntents(m, &relaySubscribeIntentsServer{ServerStream: stream})
+}
+
+type Relay_SubscribeIntentsServer interface {
+	Send(*IntentNotification) error
+	grpc.ServerStream
+}
+
+type relaySubscribeIntentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *relaySubscribeIntentsServer) Send(m *IntentNotification) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Relay_SubmitSolverOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitSolverOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayServer).SubmitSolverOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Relay_SubmitSolverOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayServer).SubmitSolverOperation(ctx, req.(*SubmitSolverOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Relay_ServiceDesc is the grpc.ServiceDesc for Relay service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Relay_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "relay.Relay",
+	HandlerType: (*RelayServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitUserOperation",
+			Handler:    _Relay_SubmitUserOperation_Handler,
+		},
+		{
+			MethodName: "GetSolverOperations",
+			Handler:    _Relay_GetSolverOperations_Handler,
+		},
+		{
+			MethodName: "SubmitSolverOperation",
+			Handler:    _Relay_SubmitSolverOperation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeIntents",
+			Handler:       _Relay_SubscribeIntents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "relay/server/pb/relay.proto",
+}
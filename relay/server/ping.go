@@ -1,7 +1,22 @@
 package server
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+)
 
 func (s *Server) ping(w http.ResponseWriter, _ *http.Request) {
-	writeResponseData(w, "pong")
+	state := s.intentService.ConnectionState()
+
+	status := http.StatusOK
+	if state != service.ConnectionStateConnected {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeResponseDataWithStatus(w, status, pingResponse{
+		Pong:               time.Now().UTC().Format(microSecTimeFormat),
+		BDNConnectionState: string(state),
+	})
 }
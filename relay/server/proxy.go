@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientAddress returns the address of the client of the request, logged as the caller. When the request comes
+// from a trusted proxy it is the last X-Forwarded-For address not of a trusted proxy, otherwise the remote address
+func (s *Server) clientAddress(r *http.Request) string {
+	if !s.trustedProxy(r.RemoteAddr) {
+		return r.RemoteAddr
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+
+	client := r.RemoteAddr
+
+	// each proxy appends the address it got the request from, the trusted ones are skipped from the right
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(forwarded[i])
+		if _, err := netip.ParseAddr(address); err != nil {
+			break
+		}
+
+		client = address
+		if !s.trustedProxy(address) {
+			break
+		}
+	}
+
+	return client
+}
+
+// trustedProxy reports whether the address, with or without a port, is one of a trusted proxy
+func (s *Server) trustedProxy(address string) bool {
+	if len(s.trustedProxies) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range s.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
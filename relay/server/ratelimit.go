@@ -0,0 +1,146 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"golang.org/x/time/rate"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/metrics"
+)
+
+// idleBucketTTL is how long the token bucket of a client is kept after its last request,
+// an idle bucket is full again long before it is dropped for any reasonable limit
+const idleBucketTTL = 10 * time.Minute
+
+// the kinds of calls limited apart, an HTTP route and a gRPC method of the same name have their own limit
+const (
+	limitRoute  = "route"
+	limitMethod = "method"
+	limitGRPC   = "grpc"
+)
+
+// rateLimiter keeps a token bucket per client for every limited route, JSON-RPC method and gRPC method
+type rateLimiter struct {
+	// limits are indexed by the kind and the lower case name of the call, the config keys are case-insensitive
+	limits map[string]config.RateLimit
+
+	lock    sync.Mutex
+	buckets *ttlcache.Cache[string, *rate.Limiter]
+}
+
+// newRateLimiter returns the rate limiter of the configured limits, nil when no limit is configured
+func newRateLimiter(cfg *config.RateLimitConfig) *rateLimiter {
+	limits := make(map[string]config.RateLimit, len(cfg.Routes)+len(cfg.Methods)+len(cfg.GRPC))
+	for kind, l := range map[string]map[string]config.RateLimit{limitRoute: cfg.Routes, limitMethod: cfg.Methods, limitGRPC: cfg.GRPC} {
+		for name, limit := range l {
+			limits[limitKey(kind, name)] = limit
+		}
+	}
+
+	if len(limits) == 0 {
+		return nil
+	}
+
+	l := &rateLimiter{
+		limits: limits,
+		buckets: ttlcache.New[string, *rate.Limiter](
+			ttlcache.WithTTL[string, *rate.Limiter](idleBucketTTL),
+		),
+	}
+
+	go l.buckets.Start()
+
+	return l
+}
+
+// limitKey returns the key of the limit of a call of the kind
+func limitKey(kind, name string) string {
+	return kind + ":" + strings.ToLower(name)
+}
+
+// Reserve takes a token from the bucket of the client for the route or method of the kind.
+// It returns zero when the call is allowed, otherwise how long the client should wait before retrying
+func (l *rateLimiter) Reserve(kind, name, client string) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	key := limitKey(kind, name)
+
+	limit, ok := l.limits[key]
+	if !ok {
+		return 0
+	}
+
+	key += "/" + client
+
+	l.lock.Lock()
+	var limiter *rate.Limiter
+	if item := l.buckets.Get(key); item != nil {
+		limiter = item.Value()
+	} else {
+		limiter = rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+		l.buckets.Set(key, limiter, ttlcache.DefaultTTL)
+	}
+	l.lock.Unlock()
+
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	if delay == 0 {
+		return 0
+	}
+
+	reservation.Cancel()
+	metrics.RequestsRateLimited.WithLabelValues(name).Inc()
+
+	return delay
+}
+
+// Stop stops the expiry of the idle buckets
+func (l *rateLimiter) Stop() {
+	if l == nil {
+		return
+	}
+
+	l.buckets.Stop()
+}
+
+// clientKey identifies the client limits apply to: its identity when it authenticated, its remote host otherwise
+func clientKey(identity, remoteAddress string) string {
+	if identity != "" {
+		return "identity:" + identity
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddress)
+	if err != nil {
+		host = remoteAddress
+	}
+
+	return "address:" + host
+}
+
+// rateLimit rejects the requests of the clients exceeding the limit of the route with 429 Too Many Requests
+func (s *Server) rateLimit(inner http.Handler, name string) http.Handler {
+	if s.rateLimiter == nil {
+		return inner
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delay := s.rateLimiter.Reserve(limitRoute, name, clientKey(identityFromContext(r.Context()), r.RemoteAddr))
+		if delay > 0 {
+			w.Header().Set("Retry-After", fmt.Sprint(int(math.Ceil(delay.Seconds()))))
+			writeErrResponse(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		inner.ServeHTTP(w, r)
+	})
+}
@@ -1,13 +1,17 @@
 package server
 
 import (
+	"bufio"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
 	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/metrics"
 )
 
 type route struct {
@@ -15,6 +19,8 @@ type route struct {
 	method      string
 	pattern     string
 	handlerFunc http.HandlerFunc
+	// permission is required from the caller when authentication is enabled, the route is public when empty
+	permission config.Permission
 }
 
 func (s *Server) setupHandlers() *mux.Router {
@@ -23,13 +29,18 @@ func (s *Server) setupHandlers() *mux.Router {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			inner.ServeHTTP(w, r)
-			logger.Info(fmt.Sprintf("served %s", name), "method", r.Method, "url", r.RequestURI, "duration", time.Since(start))
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			inner.ServeHTTP(rec, r)
+			metrics.ObserveHTTPRequest(name, r.Method, rec.status, start)
+			logger.Info(fmt.Sprintf("served %s", name), "method", r.Method, "url", r.RequestURI, "status", rec.status,
+				"caller", s.clientAddress(r), "identity", rec.identity, "duration", time.Since(start))
 		})
 	}
 
 	for _, r := range s.buildRoutes() {
 		var handler http.Handler = r.handlerFunc
+		handler = s.rateLimit(handler, r.name)
+		handler = s.authorize(handler, r.permission)
 		handler = log(handler, r.name)
 
 		router.Methods(r.method).
@@ -49,32 +60,82 @@ func (s *Server) buildRoutes() []route {
 			pattern:     "/ping",
 			handlerFunc: s.ping,
 		},
+		{
+			name:        "Metrics",
+			method:      http.MethodGet,
+			pattern:     "/metrics",
+			handlerFunc: metrics.Handler().ServeHTTP,
+			permission:  config.PermissionAdmin,
+		},
+		{
+			name:        "ListConnections",
+			method:      http.MethodGet,
+			pattern:     "/admin/connections",
+			handlerFunc: s.listConnections,
+			permission:  config.PermissionAdmin,
+		},
+	}
+
+	// the unprefixed routes are the routes of the dApp of the top level key
+	if _, ok := s.intentService.DApp(config.DefaultDAppName); ok {
+		routes = append(routes, s.dAppRoutes("")...)
 	}
 
-	if s.cfg.DAppPrivateKey != "" {
-		routes = append(routes, s.dAppRoutes()...)
+	if len(s.cfg.AllDApps()) != 0 {
+		routes = append(routes, s.dAppRoutes("/dapp/{"+dAppNameVar+"}")...)
 	}
 
-	if s.cfg.SolverPrivateKey != "" {
+	if len(s.cfg.AllSolvers()) != 0 {
 		routes = append(routes, s.solverRoutes()...)
 	}
 
 	return routes
 }
 
-func (s *Server) dAppRoutes() []route {
+// dAppRoutes returns the dApp routes under the prefix
+func (s *Server) dAppRoutes(prefix string) []route {
 	return []route{
 		{
 			name:        "SubmitUserOperation",
 			method:      http.MethodPost,
-			pattern:     "/userOperation",
+			pattern:     prefix + "/userOperation",
 			handlerFunc: s.userOperation,
+			permission:  config.PermissionDApp,
 		},
 		{
 			name:        "GetSolverOperations",
 			method:      http.MethodGet,
-			pattern:     "/solverOperations",
+			pattern:     prefix + "/solverOperations",
 			handlerFunc: s.solverOperations,
+			permission:  config.PermissionDApp,
+		},
+		{
+			name:        "StreamSolverOperations",
+			method:      http.MethodGet,
+			pattern:     prefix + "/solverOperations/stream",
+			handlerFunc: s.streamSolverOperations,
+			permission:  config.PermissionDApp,
+		},
+		{
+			name:        "BundleOperations",
+			method:      http.MethodPost,
+			pattern:     prefix + "/bundleOperations",
+			handlerFunc: s.bundleOperations,
+			permission:  config.PermissionDApp,
+		},
+		{
+			name:        "GetIntent",
+			method:      http.MethodGet,
+			pattern:     prefix + "/intent/{" + intentIDVar + "}",
+			handlerFunc: s.intentLifecycle,
+			permission:  config.PermissionDApp,
+		},
+		{
+			name:        "WebsocketDApp",
+			method:      http.MethodGet,
+			pattern:     prefix + "/ws/dapp",
+			handlerFunc: s.websocketDApp,
+			permission:  config.PermissionDApp,
 		},
 	}
 }
@@ -85,5 +146,41 @@ func (s *Server) solverRoutes() []route {
 		http.MethodGet,
 		"/ws/solver",
 		s.websocketSolver,
+		config.PermissionSolver,
 	}}
 }
+
+// statusRecorder records the status code of a response and the identity of the caller. It keeps the http.Flusher
+// and http.Hijacker capabilities of the wrapped writer, which the SSE stream and the WebSocket upgrades rely on
+type statusRecorder struct {
+	http.ResponseWriter
+	status   int
+	identity string
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+
+	// the connection is taken over by the WebSocket handler
+	r.status = http.StatusSwitchingProtocols
+
+	return hijacker.Hijack()
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 
 	"github.com/bloXroute-Labs/bdn-operations-relay/config"
 	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
@@ -17,39 +22,67 @@ import (
 
 // Server handler http calls
 type Server struct {
-	server              *http.Server
+	server *http.Server
+	// grpcServer is nil when the gRPC API is disabled
+	grpcServer          *grpc.Server
 	cfg                 *config.Config
 	intentService       *service.Intent
 	subscriptionService *service.SubscriptionManager
+	// authenticator is nil when authentication is disabled
+	authenticator Authenticator
+	// rateLimiter is nil when no rate limit is configured
+	rateLimiter *rateLimiter
+	// trustedProxies are the proxies whose X-Forwarded-For header gives the client address
+	trustedProxies []netip.Prefix
+	upgrader       *websocket.Upgrader
 }
 
 // NewServer creates and returns a new websocket server managed by feedManager
 func NewServer(ctx context.Context, cfg *config.Config) (*Server, error) {
-	subsManager := service.NewSubscriptionManager()
+	subsManager := service.NewSubscriptionManager(cfg.Subscriptions)
 	intentService, err := service.NewIntent(ctx, cfg, subsManager)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create intent service: %v", err)
 	}
 
-	// subscribe to intents right away
-	err = intentService.SubscribeToIntents(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe to intents: %v", err)
+	// subscribe right away, the subscriptions are restored by the intent service after reconnecting to the BDN
+	if len(cfg.AllSolvers()) != 0 {
+		err = intentService.SubscribeToIntents(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe to intents: %v", err)
+		}
 	}
 
-	err = intentService.SubscribeToSolutions(ctx)
+	if len(cfg.AllDApps()) != 0 {
+		err = intentService.SubscribeToSolutions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe to solutions: %v", err)
+		}
+	}
+
+	trustedProxies, err := cfg.TrustedProxyPrefixes()
 	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe to solutions: %v", err)
+		return nil, err
 	}
 
-	return &Server{
+	s := &Server{
 		cfg:                 cfg,
 		intentService:       intentService,
 		subscriptionService: subsManager,
-	}, nil
+		authenticator:       newAuthenticator(&cfg.Auth),
+		rateLimiter:         newRateLimiter(&cfg.RateLimit),
+		trustedProxies:      trustedProxies,
+		upgrader:            newUpgrader(cfg.AllowedOrigins),
+	}
+
+	if cfg.GRPCPort != 0 {
+		s.grpcServer = newGRPCServer(s)
+	}
+
+	return s, nil
 }
 
-// Start setup handlers and start http server
+// Start setup handlers and start http server, and the gRPC server when its port is set
 func (s *Server) Start(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
@@ -57,15 +90,71 @@ func (s *Server) Start(ctx context.Context) error {
 	default:
 	}
 
+	// listen on both ports before serving either, so a port in use fails the start without serving the other API
+	var grpcListener net.Listener
+	if s.grpcServer != nil {
+		var err error
+		grpcListener, err = s.listenGRPC()
+		if err != nil {
+			return err
+		}
+	}
+
+	httpListener, err := s.listenHTTP()
+	if err != nil {
+		if grpcListener != nil {
+			_ = grpcListener.Close()
+		}
+
+		return err
+	}
+
+	var eg errgroup.Group
+	if s.grpcServer != nil {
+		eg.Go(func() error {
+			err := s.serveGRPC(grpcListener)
+			if err != nil {
+				// the relay stops when either API fails, Start would not return while the HTTP server runs
+				_ = s.server.Close()
+			}
+
+			return err
+		})
+	}
+
+	eg.Go(func() error {
+		err := s.serveHTTP(httpListener)
+		if err != nil && s.grpcServer != nil {
+			s.grpcServer.Stop()
+		}
+
+		return err
+	})
+
+	return eg.Wait()
+}
+
+// listenHTTP listens on the HTTP port, the HTTP and WebSocket APIs are served by serveHTTP
+func (s *Server) listenHTTP() (net.Listener, error) {
 	s.server = &http.Server{
 		Addr:              fmt.Sprintf(":%v", s.cfg.HTTPPort),
 		ReadHeaderTimeout: time.Second * 5,
 	}
 
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on HTTP address %s: %v", s.server.Addr, err)
+	}
+
+	return listener, nil
+}
+
+// serveHTTP serves the HTTP and WebSocket APIs until the server is shut down
+func (s *Server) serveHTTP(listener net.Listener) error {
 	logger.Info("starting HTTP server", "address", s.server.Addr)
 	s.server.Handler = s.setupHandlers()
 
-	err := s.server.ListenAndServe()
+	err := s.server.Serve(listener)
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("failed to start HTTP RPC server: %v", err)
 	}
@@ -90,6 +179,23 @@ func (s *Server) Shutdown() {
 		logger.Error("failed to shutdown http server", "error", err)
 	}
 
+	if s.grpcServer != nil {
+		logger.Info("stopping gRPC server")
+
+		// the intent streams only end with the clients, stop them once the unary calls had time to complete
+		stopped := make(chan struct{})
+		go func() {
+			s.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			s.grpcServer.Stop()
+		}
+	}
+
 	logger.Info("closing intent service")
 
 	err = s.intentService.Close()
@@ -98,16 +204,23 @@ func (s *Server) Shutdown() {
 	}
 
 	s.subscriptionService.Close()
+	s.rateLimiter.Stop()
 }
 
 func writeResponseData(w http.ResponseWriter, data interface{}) {
+	writeResponseDataWithStatus(w, http.StatusOK, data)
+}
+
+func writeResponseDataWithStatus(w http.ResponseWriter, status int, data interface{}) {
 	b, err := json.Marshal(data)
 	if err != nil {
 		logger.Error("failed to marshal response data", "error", err)
 		writeErrResponse(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
 	_, err = w.Write(b)
 	if err != nil {
 		logger.Error("failed to write response", "error", err)
@@ -147,6 +260,10 @@ func parseRequest(r *http.Request, v interface{}) error {
 		return fmt.Errorf("failed to unmarshal request: %v", err)
 	}
 
+	return validateRequest(v)
+}
+
+func validateRequest(v interface{}) error {
 	validate := validator.New()
 	if err := validate.Struct(v); err != nil {
 		return fmt.Errorf("invalid request: %v", err)
@@ -2,13 +2,17 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"github.com/sourcegraph/jsonrpc2"
 	ws "github.com/sourcegraph/jsonrpc2/websocket"
 
 	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
 )
 
 const (
@@ -16,16 +20,47 @@ const (
 	writeBufferSize = 1024
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  readBufferSize,
-	WriteBufferSize: writeBufferSize,
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
+// newUpgrader returns the upgrader of the WebSocket APIs. The requests without an Origin header, which are not sent
+// by browsers, are accepted, a browser origin must be the relay host or one of allowedOrigins, "*" allows any origin
+func newUpgrader(allowedOrigins []string) *websocket.Upgrader {
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = struct{}{}
+	}
+
+	return &websocket.Upgrader{
+		ReadBufferSize:  readBufferSize,
+		WriteBufferSize: writeBufferSize,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+
+			if _, ok := allowed["*"]; ok {
+				return true
+			}
+
+			if _, ok := allowed[strings.ToLower(origin)]; ok {
+				return true
+			}
+
+			u, err := url.Parse(origin)
+
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		},
+	}
 }
 
 func (s *Server) websocketSolver(w http.ResponseWriter, r *http.Request) {
-	connection, err := upgrader.Upgrade(w, r, nil)
+	solverName := solverFromContext(r.Context())
+	solver, ok := s.intentService.Solver(solverName)
+	if !ok {
+		writeErrResponse(w, http.StatusForbidden, fmt.Sprintf("solver %s is not configured", solverName))
+		return
+	}
+
+	connection, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		logger.Error("failed upgrading connection", "err", err)
 		writeInternalErrResponse(w)
@@ -33,12 +68,27 @@ func (s *Server) websocketSolver(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	remoteAddress := s.clientAddress(r)
+	identity := identityFromContext(r.Context())
+
 	h := &wsConnHandler{
-		remoteAddress:       r.RemoteAddr,
+		connectionID: s.subscriptionService.Connect(service.ConnectionInfo{
+			Kind:          service.ConnectionKindSolverSocket,
+			RemoteAddress: remoteAddress,
+			Identity:      identity,
+			Solver:        solver.Name(),
+		}, func() { _ = connection.Close() }),
+		remoteAddress:       remoteAddress,
+		peerAddress:         r.RemoteAddr,
+		identity:            identity,
 		intentService:       s.intentService,
+		solver:              solver,
 		subscriptionService: s.subscriptionService,
+		rateLimiter:         s.rateLimiter,
 	}
 
 	asyncHandler := jsonrpc2.AsyncHandler(h)
-	_ = jsonrpc2.NewConn(context.Background(), ws.NewObjectStream(connection), asyncHandler)
+	conn := jsonrpc2.NewConn(context.Background(), ws.NewObjectStream(connection), asyncHandler)
+
+	go h.closeOnDisconnect(conn)
 }
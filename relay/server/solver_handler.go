@@ -2,9 +2,11 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/sourcegraph/jsonrpc2"
 	"github.com/valyala/fastjson"
@@ -18,8 +20,17 @@ const (
 	methodSubscribe             = "subscribe"
 	methodUnsubscribe           = "unsubscribe"
 	methodSubmitSolverOperation = "submitSolverOperation"
+	methodSubscriptionStatus    = "subscriptionStatus"
 
 	microSecTimeFormat = "2006-01-02 15:04:05.000000"
+
+	// application defined JSON-RPC error codes, they are part of the API and must not change
+	codeInvalidSolverOperation = -32001
+	codeInvalidSolverSignature = -32002
+	codeRateLimited            = -32003
+	codeUnknownIntent          = -32004
+	codeIntentExpired          = -32005
+	codeBDNRejected            = -32006
 )
 
 var (
@@ -30,18 +41,34 @@ var (
 )
 
 type wsConnHandler struct {
-	remoteAddress       string
-	intentService       *service.Intent
+	// connectionID identifies the connection in the subscription manager
+	connectionID string
+	// remoteAddress is the address of the client, logged as the caller
+	remoteAddress string
+	// peerAddress is the address the connection comes from, a proxy when the client is behind one, rate limits apply to it
+	peerAddress string
+	// identity is the authenticated caller, empty when authentication is disabled
+	identity      string
+	intentService *service.Intent
+	// solver is the solver the connection is bound to, the solver operations are submitted under its key
+	solver              *service.Solver
 	subscriptionService *service.SubscriptionManager
+	rateLimiter         *rateLimiter
 }
 
 func (h *wsConnHandler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
 	method := req.Method
 
+	if delay := h.rateLimiter.Reserve(limitMethod, method, clientKey(h.identity, h.peerAddress)); delay > 0 {
+		h.sendErrorMsg(ctx, codeRateLimited, rateLimitedErrMsg(delay), conn, req.ID)
+		return
+	}
+
 	switch method {
 	case methodPing:
 		response := pingResponse{
-			Pong: time.Now().UTC().Format(microSecTimeFormat),
+			Pong:               time.Now().UTC().Format(microSecTimeFormat),
+			BDNConnectionState: string(h.intentService.ConnectionState()),
 		}
 		if err := conn.Reply(ctx, req.ID, response); err != nil {
 			logger.Error("error replying to client", "err", err, "reqID", req.ID, "caller", h.remoteAddress)
@@ -77,28 +104,50 @@ func (h *wsConnHandler) handleSubscribe(ctx context.Context, conn *jsonrpc2.Conn
 		return
 	}
 
-	subscription, err := h.subscriptionService.Subscribe(h.remoteAddress, service.SubscriptionType(subscriptionType), conn)
+	filter, err := parseSubscriptionFilter(v)
 	if err != nil {
-		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidRequest, fmt.Sprintf("failed to subscribe: %v", err), conn, req.ID)
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, fmt.Sprintf("invalid filters: %v", err), conn, req.ID)
 		return
 	}
+	filter.Solver = h.solver.Name()
+	filter.SolverAddress = h.solver.Address()
+
+	var fromSequence uint64
+	if value := v.Get("from_sequence"); value != nil {
+		fromSequence, err = value.Uint64()
+		if err != nil {
+			h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidParams, "from_sequence must be a non-negative integer", conn, req.ID)
+			return
+		}
+	}
 
-	defer func() {
-		_ = h.subscriptionService.Unsubscribe(h.remoteAddress, subscription.ID)
-	}()
+	fromEpoch := string(v.GetStringBytes("from_epoch"))
+
+	subscription, err := h.subscriptionService.Subscribe(h.connectionID, service.SubscriptionType(subscriptionType), filter,
+		fromEpoch, fromSequence)
+	if err != nil {
+		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidRequest, fmt.Sprintf("failed to subscribe: %v", err), conn, req.ID)
+		return
+	}
 
 	response := subscribeResponse{
 		SubscriptionID: subscription.ID,
 	}
+	if fromSequence != 0 {
+		response.Replayed = &subscription.Replayed
+		response.ReplayTruncated = &subscription.ReplayTruncated
+	}
 
-	if err = conn.Notify(ctx, "subscribe", response); err != nil {
+	if err = conn.Reply(ctx, req.ID, response); err != nil {
 		logger.Error("error replying to client", "err", err, "reqID", req.ID, "caller", h.remoteAddress)
+		_ = h.subscriptionService.Unsubscribe(h.connectionID, subscription.ID)
 		return
 	}
 
-	logger.Info("client subscribed", "subscription_type", string(subscriptionType), "caller", h.remoteAddress)
+	logger.Info("client subscribed", "subscription_type", string(subscriptionType), "caller", h.remoteAddress,
+		"identity", h.identity, "solver", h.solver.Name(), "from_epoch", fromEpoch, "from_sequence", fromSequence, "replayed", subscription.Replayed)
 
-	h.handlerSubscriptionMessages(ctx, conn, subscription)
+	go h.handleSubscriptionMessages(conn, subscription)
 }
 
 // handleUnsubscribe handles the unsubscribe method
@@ -121,7 +170,7 @@ func (h *wsConnHandler) handleUnsubscribe(ctx context.Context, conn *jsonrpc2.Co
 		return
 	}
 
-	err = h.subscriptionService.Unsubscribe(h.remoteAddress, string(subscriptionID))
+	err = h.subscriptionService.Unsubscribe(h.connectionID, string(subscriptionID))
 	if err != nil {
 		h.sendErrorMsg(ctx, jsonrpc2.CodeInvalidRequest, fmt.Sprintf("failed to unsubscribe: %v", err), conn, req.ID)
 		return
@@ -132,7 +181,8 @@ func (h *wsConnHandler) handleUnsubscribe(ctx context.Context, conn *jsonrpc2.Co
 		return
 	}
 
-	logger.Info("client unsubscribed", "subscriptionID", string(subscriptionID), "caller", h.remoteAddress)
+	logger.Info("client unsubscribed", "subscriptionID", string(subscriptionID), "caller", h.remoteAddress,
+		"identity", h.identity)
 }
 
 // handleSubmitSolverOperation handles the submitSolverOperation method
@@ -161,12 +211,56 @@ func (h *wsConnHandler) handleSubmitSolverOperation(ctx context.Context, conn *j
 		return
 	}
 
-	log.Debug("client submitted solver operation", "intent_id", string(intentID), "caller", h.remoteAddress)
+	solution := intentSolution.MarshalTo(nil)
 
-	err = h.intentService.SubmitIntentSolution(context.Background(), string(intentID), intentSolution.MarshalTo(nil))
+	solverOperation, hash, err := h.intentService.DecodeSolverOperation(solution)
 	if err != nil {
-		h.sendErrorMsg(ctx, jsonrpc2.CodeInternalError, fmt.Sprintf("failed to submit solver opertaion: %v", err), conn, req.ID)
+		code := codeInvalidSolverOperation
+		if errors.Is(err, service.ErrInvalidSolverSignature) {
+			code = codeInvalidSolverSignature
+		}
+
+		h.sendErrorMsg(ctx, code, err.Error(), conn, req.ID)
+		return
 	}
+
+	log.Debug("client submitted solver operation", "intent_id", string(intentID), "from", solverOperation.From,
+		"solver_operation_hash", hash, "caller", h.remoteAddress, "identity", h.identity, "solver", h.solver.Name())
+
+	submitted, err := h.solver.SubmitIntentSolution(context.Background(), string(intentID), solution)
+	if err != nil {
+		h.sendErrorMsg(ctx, submitErrorCode(err), fmt.Sprintf("failed to submit solver operation: %v", err), conn, req.ID)
+		return
+	}
+
+	response := submitSolverOperationResponse{
+		SolutionID:          submitted.SolutionID,
+		AcceptedAt:          submitted.AcceptedAt,
+		SolverOperationHash: hash,
+	}
+
+	if err = conn.Reply(ctx, req.ID, response); err != nil {
+		logger.Error("error replying to client", "err", err, "reqID", req.ID, "caller", h.remoteAddress)
+	}
+}
+
+// submitErrorCode returns the JSON-RPC error code of an error submitting a solver operation
+func submitErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnknownIntent):
+		return codeUnknownIntent
+	case errors.Is(err, service.ErrIntentExpired):
+		return codeIntentExpired
+	case errors.Is(err, service.ErrBDNRejected):
+		return codeBDNRejected
+	default:
+		return jsonrpc2.CodeInternalError
+	}
+}
+
+// rateLimitedErrMsg is the error message of the calls rejected by the rate limits
+func rateLimitedErrMsg(delay time.Duration) string {
+	return fmt.Sprintf("rate limit exceeded, retry in %v", delay.Round(time.Millisecond))
 }
 
 // sendErrorMsg formats and sends an RPC error message back to the client
@@ -182,22 +276,98 @@ func (h *wsConnHandler) sendErrorMsg(ctx context.Context, code int, message stri
 	}
 }
 
-func (h *wsConnHandler) handlerSubscriptionMessages(ctx context.Context, conn *jsonrpc2.Conn, subscription *service.Subscription) {
-	for {
-		select {
-		case <-conn.DisconnectNotify():
-			logger.Info("client disconnected", "caller", h.remoteAddress)
+// handleSubscriptionMessages pushes the notifications of the subscription to the client
+// until the client unsubscribes or disconnects
+func (h *wsConnHandler) handleSubscriptionMessages(conn *jsonrpc2.Conn, subscription *service.Subscription) {
+	ctx := context.Background()
+
+	// the notification channel is closed once the subscription ends
+	for msg := range subscription.NotificationChannel {
+		method := methodSubscribe
+		if _, ok := msg.(*service.SubscriptionStatus); ok {
+			method = methodSubscriptionStatus
+		}
+
+		err := conn.Notify(ctx, method, msg)
+		if err != nil {
+			logger.Error("error notifying client", "err", err, "caller", h.remoteAddress)
+			_ = h.subscriptionService.Unsubscribe(h.connectionID, subscription.ID)
 			return
-		case msg, ok := <-subscription.NotificationChannel:
-			if !ok {
-				return
-			}
-
-			err := conn.Notify(ctx, "subscribe", msg)
-			if err != nil {
-				logger.Error("error replying to client", "err", err, "caller", h.remoteAddress)
-				return
-			}
 		}
+
+		subscription.Delivered()
+	}
+}
+
+// closeOnDisconnect ends the subscriptions of the connection once the client disconnects
+func (h *wsConnHandler) closeOnDisconnect(conn *jsonrpc2.Conn) {
+	<-conn.DisconnectNotify()
+
+	h.subscriptionService.Disconnect(h.connectionID)
+
+	logger.Info("client disconnected", "caller", h.remoteAddress, "identity", h.identity, "connection_id", h.connectionID)
+}
+
+// parseSubscriptionFilter parses the optional intent_id param and the optional filters object of the subscribe method
+func parseSubscriptionFilter(v *fastjson.Value) (service.SubscriptionFilter, error) {
+	filter := service.SubscriptionFilter{
+		IntentID: string(v.GetStringBytes("intent_id")),
+	}
+
+	filters := v.Get("filters")
+	if filters == nil {
+		return filter, nil
+	}
+
+	if filters.Type() != fastjson.TypeObject {
+		return filter, fmt.Errorf("filters must be an object")
+	}
+
+	addresses := []struct {
+		name  string
+		value *common.Address
+	}{
+		{"dapp_address", &filter.DAppAddress},
+		{"sender_address", &filter.SenderAddress},
+		{"to", &filter.To},
+		{"control", &filter.Control},
+	}
+
+	for _, address := range addresses {
+		value := filters.Get(address.name)
+		if value == nil {
+			continue
+		}
+
+		s, err := value.StringBytes()
+		if err != nil || !common.IsHexAddress(string(s)) {
+			return filter, fmt.Errorf("%s must be a hex address", address.name)
+		}
+
+		*address.value = common.HexToAddress(string(s))
+	}
+
+	numbers := []struct {
+		name  string
+		value *uint64
+	}{
+		{"chain_id", &filter.ChainID},
+		{"min_deadline", &filter.MinDeadline},
+	}
+
+	for _, number := range numbers {
+		value := filters.Get(number.name)
+		if value == nil {
+			continue
+		}
+
+		n, err := value.Uint64()
+		if err != nil {
+			return filter, fmt.Errorf("%s must be a non-negative integer", number.name)
+		}
+
+		*number.value = n
 	}
+
+	return filter, nil
 }
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/store"
+)
+
+// auctions tracks the open auctions of the intents of a dApp
+type auctions struct {
+	lock sync.Mutex
+	open map[string]*auction
+}
+
+// auction is an open auction, closed is closed once its result is stored
+type auction struct {
+	timer  *time.Timer
+	closed chan struct{}
+}
+
+func newAuctions() *auctions {
+	return &auctions{
+		open: make(map[string]*auction),
+	}
+}
+
+// AuctionEnabled reports whether the dApp auctions the solutions of its intents
+func (d *DApp) AuctionEnabled() bool {
+	return d.cfg.Auction.Enabled()
+}
+
+// openAuction starts the auction window of an intent the dApp just submitted
+func (d *DApp) openAuction(intentID string) {
+	d.auctions.lock.Lock()
+	defer d.auctions.lock.Unlock()
+
+	d.auctions.open[intentID] = &auction{
+		timer:  time.AfterFunc(d.cfg.Auction.Duration(), func() { d.closeAuction(intentID) }),
+		closed: make(chan struct{}),
+	}
+}
+
+// closeAuction freezes the solutions received for the intent during its auction window: they are ranked by bid,
+// the bids below the minimum are dropped and the result is capped at the maximum solver count
+func (d *DApp) closeAuction(intentID string) {
+	d.auctions.lock.Lock()
+	a, ok := d.auctions.open[intentID]
+	d.auctions.lock.Unlock()
+
+	if !ok {
+		return
+	}
+
+	// the auction stays open until its result is stored, so no bundle is built from the solutions received
+	defer func() {
+		d.auctions.lock.Lock()
+		delete(d.auctions.open, intentID)
+		d.auctions.lock.Unlock()
+
+		close(a.closed)
+	}()
+
+	intent, ok := d.storedIntent(intentID)
+	if !ok {
+		return
+	}
+
+	received := make([]types.SolverOperationRaw, 0, len(intent.Solutions))
+	for _, solution := range intent.Solutions {
+		received = append(received, solution.SolverOperation)
+	}
+
+	ranked := d.filterSolverOperations(received)
+	SortSolverOperationsByBid(ranked)
+
+	solverOps := make([]types.SolverOperationRaw, 0, len(ranked))
+	minBid := d.cfg.Auction.MinBidAmount()
+
+	for _, op := range ranked {
+		if minBid != nil && bidAmount(&op).Cmp(minBid) < 0 {
+			// the operations are ranked, all the following ones bid less
+			break
+		}
+
+		if d.cfg.Auction.MaxSolvers > 0 && len(solverOps) == d.cfg.Auction.MaxSolvers {
+			break
+		}
+
+		solverOps = append(solverOps, op)
+	}
+
+	err := d.intent.store.CloseAuction(intentID, &store.Auction{
+		ClosedAt:         time.Now(),
+		SolverOperations: solverOps,
+	})
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		logger.Error("failed to store auction result", "error", err, "intent_id", intentID)
+		return
+	}
+
+	logger.Debug("auction closed", "intent_id", intentID, "received", len(received), "selected", len(solverOps))
+}
+
+// auctionOpen reports whether the auction window of the intent is still open
+func (d *DApp) auctionOpen(intentID string) bool {
+	d.auctions.lock.Lock()
+	defer d.auctions.lock.Unlock()
+
+	_, ok := d.auctions.open[intentID]
+
+	return ok
+}
+
+// Auctioned reports whether the relay auctions the solutions of the intent, whether or not its auction closed.
+// The intents submitted before a restart of the relay are not auctioned
+func (d *DApp) Auctioned(intentID string) bool {
+	return d.AuctionClosed(intentID) != nil
+}
+
+// AuctionClosed returns a channel closed once the auction of the intent is over, closed right away when it already is.
+// The channel is nil, and never ready, when the intent was not auctioned by the relay
+func (d *DApp) AuctionClosed(intentID string) <-chan struct{} {
+	d.auctions.lock.Lock()
+	a, ok := d.auctions.open[intentID]
+	d.auctions.lock.Unlock()
+
+	if ok {
+		return a.closed
+	}
+
+	if _, ok = d.Auction(intentID); !ok {
+		return nil
+	}
+
+	closed := make(chan struct{})
+	close(closed)
+
+	return closed
+}
+
+// Auction returns the result of the auction of the intent, ok is false while the auction is open
+// or when the intent was not auctioned by the relay
+func (d *DApp) Auction(intentID string) (*store.Auction, bool) {
+	intent, ok := d.storedIntent(intentID)
+	if !ok || intent.Auction == nil {
+		return nil, false
+	}
+
+	return intent.Auction, true
+}
+
+// WaitForAuction blocks until the auction of the intent is over or ctx is done and returns its result,
+// ok is false when the auction is still open or the intent was not auctioned by the relay
+func (d *DApp) WaitForAuction(ctx context.Context, intentID string) (*store.Auction, bool) {
+	closed := d.AuctionClosed(intentID)
+	if closed == nil {
+		return nil, false
+	}
+
+	select {
+	case <-closed:
+		return d.Auction(intentID)
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// stopAuctions stops the auction windows still open, their results are not recorded
+func (d *DApp) stopAuctions() {
+	d.auctions.lock.Lock()
+	defer d.auctions.lock.Unlock()
+
+	for intentID, a := range d.auctions.open {
+		a.timer.Stop()
+		delete(d.auctions.open, intentID)
+	}
+}
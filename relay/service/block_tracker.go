@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+)
+
+const blockPollInterval = 2 * time.Second
+
+// blockTracker keeps track of the latest block number of the Atlas chain,
+// solver operation deadlines are expressed in block numbers
+type blockTracker struct {
+	client *ethclient.Client
+	latest atomic.Uint64
+}
+
+// newBlockTracker polls the latest block number from the node until ctx is done
+func newBlockTracker(ctx context.Context, client *ethclient.Client) (*blockTracker, error) {
+	b := &blockTracker{
+		client: client,
+	}
+
+	err := b.poll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	go b.run(ctx)
+
+	return b, nil
+}
+
+// BlockNumber returns the latest known block number, ok is false when the block number is not tracked
+func (b *blockTracker) BlockNumber() (number uint64, ok bool) {
+	if b == nil {
+		return 0, false
+	}
+
+	number = b.latest.Load()
+
+	return number, number != 0
+}
+
+func (b *blockTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(blockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := b.poll(ctx)
+			if err != nil {
+				logger.Warn("failed to get latest block number", "error", err)
+			}
+		}
+	}
+}
+
+func (b *blockTracker) poll(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, blockPollInterval)
+	defer cancel()
+
+	number, err := b.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	b.latest.Store(number)
+
+	return nil
+}
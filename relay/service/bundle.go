@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	atlasconfig "github.com/FastLane-Labs/atlas-sdk-go/config"
+	"github.com/FastLane-Labs/atlas-sdk-go/core"
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/store"
+)
+
+var (
+	ErrIntentNotFound          = errors.New("intent is not tracked by the relay")
+	ErrSolverOperationNotFound = errors.New("solver operation not found")
+	ErrNoSolverOperations      = errors.New("no solver operations available for the intent")
+	ErrAuctionOpen             = errors.New("auction of the intent is still open")
+)
+
+// userOperationEntry is a user operation submitted through the relay with the solver operations received for it,
+// kept to build its bundle
+type userOperationEntry struct {
+	chainID          uint64
+	userOperation    *types.UserOperation
+	solverOperations []types.SolverOperationRaw
+}
+
+// BundleParams selects the solver operations of a bundle and the fields of its dApp operation
+type BundleParams struct {
+	IntentID string
+	// SolverOperationHashes are the EIP-712 hashes of the chosen solver operations in bundle order,
+	// all valid solver operations ranked by bid are used when empty
+	SolverOperationHashes []common.Hash
+	Bundler               common.Address
+	Nonce                 *big.Int
+}
+
+// BuildBundle builds the Atlas bundle of an intent submitted through the relay: the user operation,
+// the chosen solver operations and the dApp operation signed with the dApp key
+func (d *DApp) BuildBundle(ctx context.Context, params *BundleParams) (*types.BundleRaw, error) {
+	// the bundle of an auctioned intent is built from the result of its auction, which is not known yet
+	if d.auctionOpen(params.IntentID) {
+		return nil, ErrAuctionOpen
+	}
+
+	entry, ok := d.userOperation(params.IntentID)
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+
+	userOp := entry.userOperation
+
+	userOpHash, err := hashUserOperation(userOp, entry.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash user operation: %w", err)
+	}
+
+	solverOps, err := d.bundleSolverOperations(params, entry.solverOperations, userOpHash)
+	if err != nil {
+		return nil, err
+	}
+
+	dAppOp, err := d.signDAppOperation(ctx, entry, userOpHash, solverOps, params.Bundler, params.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &types.Bundle{
+		ChainId:          entry.chainID,
+		UserOperation:    userOp,
+		SolverOperations: solverOps,
+		DAppOperation:    dAppOp,
+	}
+
+	d.setIntentStatus(params.IntentID, store.IntentStatusBundled)
+
+	return bundle.EncodeToRaw(), nil
+}
+
+// bundleSolverOperations returns the solver operations of the bundle, only operations solving the user operation are accepted
+func (d *DApp) bundleSolverOperations(params *BundleParams, received []types.SolverOperationRaw,
+	userOpHash common.Hash) (types.SolverOperations, error) {
+	available := d.filterSolverOperations(received)
+
+	var solverOps types.SolverOperations
+
+	if len(params.SolverOperationHashes) == 0 {
+		SortSolverOperationsByBid(available)
+
+		for j := range available {
+			if available[j].UserOpHash == userOpHash {
+				solverOps = append(solverOps, available[j].Decode())
+			}
+		}
+
+		if len(solverOps) == 0 {
+			return nil, ErrNoSolverOperations
+		}
+
+		return solverOps, nil
+	}
+
+	byHash := make(map[common.Hash]*types.SolverOperationRaw, len(available))
+	for j := range available {
+		hash, err := d.solverOperationHash(&available[j])
+		if err == nil && available[j].UserOpHash == userOpHash {
+			byHash[hash] = &available[j]
+		}
+	}
+
+	for _, hash := range params.SolverOperationHashes {
+		op, ok := byHash[hash]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrSolverOperationNotFound, hash)
+		}
+
+		solverOps = append(solverOps, op.Decode())
+	}
+
+	return solverOps, nil
+}
+
+// signDAppOperation builds the dApp operation approving the execution of the solver operations for the user operation
+// and signs it with the dApp key
+func (d *DApp) signDAppOperation(ctx context.Context, entry *userOperationEntry, userOpHash common.Hash, solverOps types.SolverOperations,
+	bundler common.Address, nonce *big.Int) (*types.DAppOperation, error) {
+	contracts, err := atlasContracts(entry.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	callChainHash, err := core.CallChainHash(entry.userOperation, solverOps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute call chain hash: %w", err)
+	}
+
+	dAppOp := &types.DAppOperation{
+		From:          d.signer.Address(),
+		To:            contracts.Atlas,
+		Nonce:         nonce,
+		Deadline:      entry.userOperation.Deadline,
+		Control:       entry.userOperation.Control,
+		Bundler:       bundler,
+		UserOpHash:    userOpHash,
+		CallChainHash: callChainHash,
+	}
+
+	// the nil numbers of the dApp operation are hashed as zero
+	dAppOp.Sanitize()
+
+	typedData, err := dAppOperationTypedData(dAppOp, entry.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	dAppOp.Signature, err = d.signer.SignTypedData(ctx, typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign dApp operation: %w", err)
+	}
+
+	// Atlas expects V to be 27 or 28
+	dAppOp.Signature[crypto.RecoveryIDOffset] += 27
+
+	// the typed data is built apart from the Atlas SDK, make sure it still hashes to the dApp operation hash
+	err = validateDAppOperationSignature(dAppOp, entry.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign dApp operation: %w", err)
+	}
+
+	return dAppOp, nil
+}
+
+// dAppOperationTypedData returns the EIP-712 typed data of the dApp operation, as hashed by the Atlas SDK
+func dAppOperationTypedData(dAppOp *types.DAppOperation, chainID uint64) (*apitypes.TypedData, error) {
+	domain, err := atlasconfig.GetEip712Domain(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get EIP-712 domain: %w", err)
+	}
+
+	// the domain of the chain config is shared, the chain id is encoded in place when the typed data is hashed
+	typedDomain := *domain
+	typedDomain.ChainId = eip712ChainID(domain)
+
+	return &apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": []apitypes.Type{
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"DAppOperation": []apitypes.Type{
+				{Name: "from", Type: "address"},
+				{Name: "to", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+				{Name: "control", Type: "address"},
+				{Name: "bundler", Type: "address"},
+				{Name: "userOpHash", Type: "bytes32"},
+				{Name: "callChainHash", Type: "bytes32"},
+			},
+		},
+		PrimaryType: "DAppOperation",
+		Domain:      typedDomain,
+		Message: apitypes.TypedDataMessage{
+			"from":          dAppOp.From.Hex(),
+			"to":            dAppOp.To.Hex(),
+			"nonce":         dAppOp.Nonce.String(),
+			"deadline":      dAppOp.Deadline.String(),
+			"control":       dAppOp.Control.Hex(),
+			"bundler":       dAppOp.Bundler.Hex(),
+			"userOpHash":    dAppOp.UserOpHash.Hex(),
+			"callChainHash": dAppOp.CallChainHash.Hex(),
+		},
+	}, nil
+}
+
+// atlasContracts returns the Atlas contract addresses of the chain
+func atlasContracts(chainID uint64) (*atlasconfig.Contract, error) {
+	chainConfig, err := atlasconfig.GetChainConfig(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if chainConfig.Contract == nil {
+		return nil, fmt.Errorf("no Atlas contracts deployed on chain %d", chainID)
+	}
+
+	return chainConfig.Contract, nil
+}
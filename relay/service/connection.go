@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+	"github.com/bloXroute-Labs/bloxroute-sdk-go/connection/ws"
+	"github.com/cenkalti/backoff/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+)
+
+const (
+	ConnectionStateConnecting   ConnectionState = "connecting"
+	ConnectionStateConnected    ConnectionState = "connected"
+	ConnectionStateDisconnected ConnectionState = "disconnected"
+
+	reconnectInitialInterval = 500 * time.Millisecond
+	reconnectMaxInterval     = 30 * time.Second
+	wsHandshakeTimeout       = time.Minute
+)
+
+var ErrNotConnected = errors.New("not connected to BDN")
+
+// ConnectionState is the state of the connection to the BDN gateway
+type ConnectionState string
+
+// subscribeFunc registers a subscription on the given client.
+// onError must be called when the subscription stream breaks, so the connection can be re-established
+type subscribeFunc func(ctx context.Context, client *sdk.Client, onError func(error)) error
+
+// connection supervises a BDN client: it detects disconnects, re-dials the gateway
+// with exponential backoff and re-registers all subscriptions on the new client
+type connection struct {
+	cfg *config.BDNConfig
+
+	lock       sync.RWMutex
+	client     *sdk.Client
+	state      ConnectionState
+	generation uint64
+
+	// subLock serializes subscription registration with (re)connecting
+	subLock       sync.Mutex
+	subscriptions []subscribeFunc
+
+	lost   chan struct{}
+	stop   chan struct{}
+	closed atomic.Bool
+}
+
+// newConnection dials the BDN gateway and starts supervising the connection until ctx is done
+func newConnection(ctx context.Context, cfg *config.BDNConfig) (*connection, error) {
+	c := &connection{
+		cfg:   cfg,
+		state: ConnectionStateDisconnected,
+		lost:  make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+	}
+
+	err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	go c.supervise(ctx)
+
+	return c, nil
+}
+
+// Client returns the current BDN client or ErrNotConnected if the connection is being re-established
+func (c *connection) Client() (*sdk.Client, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.client == nil || c.state != ConnectionStateConnected {
+		return nil, ErrNotConnected
+	}
+
+	return c.client, nil
+}
+
+// State returns the current connection state
+func (c *connection) State() ConnectionState {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.state
+}
+
+// Subscribe registers a subscription on the current client.
+// The subscription is registered again every time the connection is re-established
+func (c *connection) Subscribe(ctx context.Context, fn subscribeFunc) error {
+	c.subLock.Lock()
+	defer c.subLock.Unlock()
+
+	c.lock.RLock()
+	client, generation := c.client, c.generation
+	c.lock.RUnlock()
+
+	if client != nil {
+		err := fn(ctx, client, c.streamErrorHandler(generation))
+		if err != nil {
+			return err
+		}
+	}
+
+	c.subscriptions = append(c.subscriptions, fn)
+
+	return nil
+}
+
+// Close stops supervising and closes the current client
+func (c *connection) Close() error {
+	if !c.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	close(c.stop)
+
+	c.lock.Lock()
+	client := c.client
+	c.client = nil
+	c.state = ConnectionStateDisconnected
+	c.lock.Unlock()
+
+	if client == nil {
+		return nil
+	}
+
+	// closing waits for the subscription callbacks, which may take the lock themselves
+	return client.Close()
+}
+
+func (c *connection) supervise(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.stop:
+			return
+		case <-c.lost:
+		}
+
+		c.reconnect(ctx)
+	}
+}
+
+func (c *connection) reconnect(ctx context.Context) {
+	c.lock.Lock()
+	client := c.client
+	c.client = nil
+	c.lock.Unlock()
+
+	if client != nil {
+		err := client.Close()
+		if err != nil {
+			logger.Debug("failed to close broken BDN client", "error", err)
+		}
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = reconnectInitialInterval
+	b.MaxInterval = reconnectMaxInterval
+	b.MaxElapsedTime = 0
+
+	notify := func(err error, next time.Duration) {
+		logger.Warn("failed to reconnect to BDN", "error", err, "retry_in", next)
+	}
+
+	err := backoff.RetryNotify(func() error {
+		select {
+		case <-c.stop:
+			return backoff.Permanent(ErrNotConnected)
+		default:
+		}
+
+		return c.connect(ctx)
+	}, backoff.WithContext(b, ctx), notify)
+	if err != nil {
+		logger.Debug("stopped reconnecting to BDN", "error", err)
+	}
+}
+
+// connect dials a new client and registers all subscriptions on it
+func (c *connection) connect(ctx context.Context) error {
+	c.subLock.Lock()
+	defer c.subLock.Unlock()
+
+	c.lock.Lock()
+	c.generation++
+	generation := c.generation
+	c.state = ConnectionStateConnecting
+	c.lock.Unlock()
+
+	client, err := sdk.NewClient(ctx, c.sdkConfig(generation))
+	if err != nil {
+		c.setState(generation, ConnectionStateDisconnected)
+		return fmt.Errorf("failed to create BDN client: %w", err)
+	}
+
+	for _, fn := range c.subscriptions {
+		err = fn(ctx, client, c.streamErrorHandler(generation))
+		if err != nil {
+			_ = client.Close()
+			c.setState(generation, ConnectionStateDisconnected)
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.closed.Load() {
+		_ = client.Close()
+		return backoff.Permanent(ErrNotConnected)
+	}
+
+	c.client = client
+	c.state = ConnectionStateConnected
+
+	logger.Info("connected to BDN", "url", c.url(), "subscriptions", len(c.subscriptions))
+
+	return nil
+}
+
+func (c *connection) setState(generation uint64, state ConnectionState) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.generation == generation {
+		c.state = state
+	}
+}
+
+// failureHandler returns a callback that reports the loss of the client of the given generation.
+// Errors reported by previous generations are ignored, they belong to clients that were already replaced
+func (c *connection) failureHandler(generation uint64) func(error) {
+	return func(err error) {
+		if c.closed.Load() {
+			return
+		}
+
+		c.lock.Lock()
+		if c.generation != generation || c.state == ConnectionStateDisconnected {
+			c.lock.Unlock()
+			return
+		}
+		c.state = ConnectionStateDisconnected
+		c.lock.Unlock()
+
+		logger.Warn("lost connection to BDN, reconnecting", "url", c.url(), "error", err)
+
+		select {
+		case c.lost <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// streamErrorHandler returns the handler for errors delivered to subscription callbacks.
+// gRPC streams report a broken connection this way, while WS callbacks only receive
+// errors about malformed notifications and WS disconnects are detected by monitoredWSConn
+func (c *connection) streamErrorHandler(generation uint64) func(error) {
+	if c.isGRPC() {
+		return c.failureHandler(generation)
+	}
+
+	return func(error) {}
+}
+
+// isGRPC reports whether the gateway is reached over gRPC
+func (c *connection) isGRPC() bool {
+	return c.cfg.GRPCURL != ""
+}
+
+func (c *connection) url() string {
+	if c.isGRPC() {
+		return c.cfg.GRPCURL
+	}
+
+	return c.cfg.WSURL
+}
+
+func (c *connection) sdkConfig(generation uint64) *sdk.Config {
+	sdkConfig := &sdk.Config{
+		AuthHeader: c.cfg.AuthHeader,
+		Logger:     new(logger.Instance),
+	}
+
+	if c.isGRPC() {
+		transportCredentials := credentials.NewClientTLSFromCert(nil, "")
+		if c.cfg.GRPCInsecure {
+			transportCredentials = insecure.NewCredentials()
+		}
+
+		sdkConfig.GRPCDialOptions = []grpc.DialOption{
+			grpc.WithTransportCredentials(transportCredentials),
+		}
+		sdkConfig.GRPCGatewayURL = c.cfg.GRPCURL
+
+		return sdkConfig
+	}
+
+	// reconnecting is handled by the connection itself, so that subscriptions
+	// are re-registered and the state is tracked
+	reconnect := false
+	onError := c.failureHandler(generation)
+
+	sdkConfig.Reconnect = &reconnect
+	sdkConfig.WSDialOptions = &ws.DialOptions{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+		HandshakeTimeout: wsHandshakeTimeout,
+	}
+	sdkConfig.WSGatewayURL = c.cfg.WSURL
+	sdkConfig.WSConnectFunc = func(ctx context.Context, url string, headers http.Header, opts *ws.DialOptions) (ws.Conn, error) {
+		conn, err := ws.Dial(ctx, url, headers, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &monitoredWSConn{Conn: conn, onError: onError}, nil
+	}
+
+	return sdkConfig
+}
+
+// monitoredWSConn reports read errors of a WS connection that was not closed by the relay
+type monitoredWSConn struct {
+	ws.Conn
+	closing atomic.Bool
+	onError func(error)
+}
+
+func (m *monitoredWSConn) ReadMessage(ctx context.Context) ([]byte, error) {
+	msg, err := m.Conn.ReadMessage(ctx)
+	if err != nil && !m.closing.Load() && ctx.Err() == nil {
+		m.onError(err)
+	}
+
+	return msg, err
+}
+
+func (m *monitoredWSConn) Close() error {
+	m.closing.Store(true)
+	return m.Conn.Close()
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/valyala/fastjson"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/metrics"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/signer"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/store"
+)
+
+var ErrInvalidUserOperation = errors.New("invalid user operation parameters")
+
+// intentTrackingTTL is how long the solutions of an intent are delivered live to the watchers,
+// the intent and its solutions are kept in the store for the retention period
+const intentTrackingTTL = time.Minute
+
+// DApp submits the user operations of a dApp to the BDN under its own key
+// and tracks the solver operations of its intents apart from the other dApps
+type DApp struct {
+	intent   *Intent
+	conn     *connection
+	cfg      config.DAppConfig
+	signer   signer.Signer
+	cache    *ttlcache.Cache[string, []types.SolverOperationRaw]
+	watchers *solutionWatchers
+	auctions *auctions
+	// simulations are the recent simulation results of the solver operations
+	simulations *ttlcache.Cache[simulationKey, *SimulationResult]
+	// ethClient and blocks are only set when the dApp is on the chain of the Atlas chain node
+	ethClient *ethclient.Client
+	blocks    *blockTracker
+}
+
+func newDApp(ctx context.Context, intent *Intent, conn *connection, cfg config.DAppConfig) (*DApp, error) {
+	s, err := signer.New(ctx, cfg.PrivateKey, &cfg.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer of dApp %s: %w", cfg.Name, err)
+	}
+
+	d := &DApp{
+		intent: intent,
+		conn:   conn,
+		cfg:    cfg,
+		signer: s,
+		cache: ttlcache.New[string, []types.SolverOperationRaw](
+			ttlcache.WithTTL[string, []types.SolverOperationRaw](intentTrackingTTL),
+		),
+		watchers: newSolutionWatchers(),
+		auctions: newAuctions(),
+		simulations: ttlcache.New[simulationKey, *SimulationResult](
+			ttlcache.WithTTL[simulationKey, *SimulationResult](intentTrackingTTL),
+		),
+	}
+
+	if cfg.ChainID == intent.cfg.Atlas.ChainID {
+		d.ethClient = intent.ethClient
+		d.blocks = intent.blocks
+	}
+
+	if d.blocks == nil {
+		logger.Warn("solver operation deadlines are not checked without a node of the dApp chain in atlas.eth-rpc-url",
+			"dapp", cfg.Name, "chain_id", cfg.ChainID)
+	}
+
+	d.cache.OnEviction(d.onIntentExpired)
+
+	go d.cache.Start()
+	go d.simulations.Start()
+
+	return d, nil
+}
+
+// Name returns the name of the dApp
+func (d *DApp) Name() string {
+	return d.cfg.Name
+}
+
+// SubmitUserOperation submits the partial user operation to the BDN as an intent
+// and starts tracking the solutions received for it
+func (d *DApp) SubmitUserOperation(ctx context.Context, chainID uint64, userOp *types.UserOperation, hints []common.Address) (intentID string, err error) {
+	defer func() {
+		metrics.UserOperationsSubmitted.WithLabelValues(metrics.Status(err)).Inc()
+	}()
+
+	if chainID != d.cfg.ChainID {
+		return "", fmt.Errorf("%w: chain id %d does not match the dApp chain id %d", ErrInvalidUserOperation, chainID, d.cfg.ChainID)
+	}
+
+	partialOperation, err := newUserOperationPartial(chainID, userOp, hints)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidUserOperation, err)
+	}
+
+	data, err := json.Marshal(partialOperation)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal user operation partial: %w", err)
+	}
+
+	intentID, err = d.SubmitIntent(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	// keep the full user operation, the dApp operation of the bundle is built from it
+	userOp.Sanitize()
+
+	now := time.Now()
+	err = d.intent.store.PutIntent(&store.Intent{
+		ID:            intentID,
+		DApp:          d.cfg.Name,
+		ChainID:       chainID,
+		UserOperation: userOp.EncodeToRaw(),
+		Status:        store.IntentStatusOpen,
+		SubmittedAt:   now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store intent %s: %w", intentID, err)
+	}
+
+	d.SubscribeToIntentSolutions(intentID)
+
+	if d.AuctionEnabled() {
+		d.openAuction(intentID)
+	}
+
+	return intentID, nil
+}
+
+// SubmitIntent submits an intent to the BDN
+func (d *DApp) SubmitIntent(ctx context.Context, intent []byte) (string, error) {
+	hash, signature, err := signer.SignPayload(ctx, d.signer, intent)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign intent: %w", err)
+	}
+
+	params := &sdk.SubmitIntentParams{
+		DappAddress:   d.cfg.Address,
+		SenderAddress: d.signer.Address().Hex(),
+		Intent:        intent,
+		Hash:          hash,
+		Signature:     signature,
+	}
+
+	client, err := d.conn.Client()
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	resp, err := client.SubmitIntent(ctx, params)
+	metrics.ObserveBDNRequest("SubmitIntent", start, err)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit intent: %w", err)
+	}
+
+	var p fastjson.Parser
+	v, err := p.ParseBytes(*resp)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	return string(v.GetStringBytes("intent_id")), nil
+}
+
+// userOperation returns the user operation of an intent the dApp submitted through the relay
+// and the solver operations received for it
+func (d *DApp) userOperation(intentID string) (*userOperationEntry, bool) {
+	intent, ok := d.storedIntent(intentID)
+	if !ok {
+		return nil, false
+	}
+
+	entry := &userOperationEntry{
+		chainID:          intent.ChainID,
+		userOperation:    intent.UserOperation.Decode(),
+		solverOperations: make([]types.SolverOperationRaw, 0, len(intent.Solutions)),
+	}
+
+	// the bundle of an auctioned intent is built from the result of its auction
+	if intent.Auction != nil {
+		entry.solverOperations = append(entry.solverOperations, intent.Auction.SolverOperations...)
+		return entry, true
+	}
+
+	for _, solution := range intent.Solutions {
+		entry.solverOperations = append(entry.solverOperations, solution.SolverOperation)
+	}
+
+	return entry, true
+}
+
+// storedIntent returns the intent of the store if the dApp submitted it
+func (d *DApp) storedIntent(intentID string) (*store.Intent, bool) {
+	intent, err := d.intent.store.Intent(intentID)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			logger.Error("failed to read intent from store", "error", err, "intent_id", intentID)
+		}
+		return nil, false
+	}
+
+	if intent.DApp != d.cfg.Name {
+		return nil, false
+	}
+
+	return intent, true
+}
+
+// setIntentStatus records the status of an intent submitted through the relay
+func (d *DApp) setIntentStatus(intentID string, status store.IntentStatus) {
+	err := d.intent.store.SetStatus(intentID, status)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		logger.Error("failed to update intent status", "error", err, "intent_id", intentID, "status", status)
+	}
+}
+
+// GetIntentSolutions gets list of solutions for a specific intent
+func (d *DApp) GetIntentSolutions(ctx context.Context, intentID string) ([]types.SolverOperationRaw, error) {
+	// check if we have the solutions in cache
+	item := d.cache.Get(intentID)
+	if item != nil && len(item.Value()) != 0 {
+		logger.Debug("returning cached intent solutions", "intent_id", intentID)
+		return d.filterSolverOperations(item.Value()), nil
+	}
+
+	// the store keeps the solutions of the intents submitted through the relay past the tracking window and restarts
+	if entry, ok := d.userOperation(intentID); ok && len(entry.solverOperations) != 0 {
+		logger.Debug("returning stored intent solutions", "intent_id", intentID)
+		return d.filterSolverOperations(entry.solverOperations), nil
+	}
+
+	address := d.signer.Address().Hex()
+
+	hash, signature, err := signer.SignPayload(ctx, d.signer, []byte(address+intentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign intent solutions request: %w", err)
+	}
+
+	params := &sdk.GetSolutionsForIntentParams{
+		DAppOrSenderAddress: address,
+		IntentID:            intentID,
+		Hash:                hash,
+		Signature:           signature,
+	}
+
+	client, err := d.conn.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := client.GetSolutionsForIntent(ctx, params)
+	metrics.ObserveBDNRequest("GetSolutionsForIntent", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intent solutions: %w", err)
+	}
+
+	var p fastjson.Parser
+	v, err := p.ParseBytes(*resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	var result []types.SolverOperationRaw
+
+	for _, obj := range v.GetArray() {
+		intentSolution := obj.Get("intent_solution").GetStringBytes()
+
+		out := make([]byte, base64.StdEncoding.DecodedLen(len(intentSolution)))
+		n, err := base64.StdEncoding.Decode(out, intentSolution)
+		if err != nil {
+			logger.Error("failed to decode intent solution from base64", "error", err, "intent_solution", string(intentSolution))
+			continue
+		}
+
+		var solverOperation *types.SolverOperationRaw
+		err = json.Unmarshal(out[:n], &solverOperation) // TODO use var p fastjson.Parser
+		if err != nil {
+			logger.Error("failed to unmarshal intent solution into SolverOperationRaw", "error", err,
+				"intent_solution", string(intentSolution))
+			continue
+		}
+
+		result = append(result, *solverOperation)
+	}
+
+	return d.filterSolverOperations(result), nil
+}
+
+// SubscribeToSolutions subscribes to the solutions of the dApp intents, the subscription is restored after reconnecting
+func (d *DApp) SubscribeToSolutions(ctx context.Context) error {
+	logger.Debug("subscribing to intent solutions", "dapp", d.cfg.Name)
+
+	address := d.signer.Address().Hex()
+
+	// the signature does not change, it is reused when the subscription is restored
+	hash, signature, err := signer.SignPayload(ctx, d.signer, []byte(address))
+	if err != nil {
+		return fmt.Errorf("failed to sign intent solutions subscription: %w", err)
+	}
+
+	params := &sdk.IntentSolutionsParams{
+		DappAddress: address,
+		Hash:        hash,
+		Signature:   signature,
+	}
+
+	err = d.conn.Subscribe(ctx, func(ctx context.Context, client *sdk.Client, onError func(error)) error {
+		return client.OnIntentSolutions(ctx, params, func(ctx context.Context, err error, result *sdk.OnIntentSolutionsNotification) {
+			if err != nil {
+				logger.Error("error receiving intent solution", "error", err)
+				onError(err)
+				return
+			}
+
+			d.onIntentSolution(result)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to intent solutions: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DApp) onIntentSolution(result *sdk.OnIntentSolutionsNotification) {
+	logger.Debug("received intent solution", "dapp", d.cfg.Name, "intent_id", result.IntentID)
+
+	metrics.SolverOperationsReceived.Inc()
+
+	// WS notifications carry the solution base64 encoded, gRPC ones carry the raw bytes
+	rawSolution := make([]byte, base64.StdEncoding.DecodedLen(len(result.IntentSolution)))
+	n, err := base64.StdEncoding.Decode(rawSolution, result.IntentSolution)
+	if err == nil {
+		rawSolution = rawSolution[:n]
+	} else {
+		rawSolution = result.IntentSolution
+	}
+
+	var solverOperation *types.SolverOperationRaw
+	err = json.Unmarshal(rawSolution, &solverOperation)
+	if err != nil {
+		logger.Error("failed to unmarshal intent solution into SolverOperationRaw", "error", err,
+			"intent_solution", string(result.IntentSolution))
+		return
+	}
+
+	d.watchers.lock.Lock()
+	defer d.watchers.lock.Unlock()
+
+	item := d.cache.Get(result.IntentID)
+	if item == nil {
+		return
+	}
+
+	hash, err := d.solverOperationHash(solverOperation)
+	if err != nil {
+		logger.Error("failed to compute solver operation hash", "error", err, "intent_id", result.IntentID)
+		return
+	}
+
+	// the BDN may deliver the same solver operation more than once
+	if !d.watchers.markReceived(result.IntentID, hash) {
+		logger.Debug("ignoring duplicate intent solution", "intent_id", result.IntentID, "solver_operation_hash", hash)
+		return
+	}
+
+	v := append(item.Value(), *solverOperation)
+
+	d.cache.Set(result.IntentID, v, ttlcache.DefaultTTL)
+
+	err = d.intent.store.AddSolution(result.IntentID, &store.Solution{
+		ID:              result.SolutionID,
+		SolverOperation: *solverOperation,
+		ReceivedAt:      time.Now(),
+	})
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		logger.Error("failed to store intent solution", "error", err, "intent_id", result.IntentID)
+	}
+
+	d.watchers.notify(result.IntentID, *solverOperation)
+
+	// the intentSolution subscribers get each solution once, like the watchers
+	d.intent.subscriptionManager.Notify(&IntentSolutionNotification{
+		IntentID:        result.IntentID,
+		SolutionID:      result.SolutionID,
+		SolverOperation: solverOperation,
+	})
+}
+
+// onIntentExpired closes the watchers of an intent that was evicted from the cache
+func (d *DApp) onIntentExpired(_ context.Context, _ ttlcache.EvictionReason, item *ttlcache.Item[string, []types.SolverOperationRaw]) {
+	d.watchers.lock.Lock()
+	defer d.watchers.lock.Unlock()
+
+	// the intent may have been tracked again in the meantime
+	if d.cache.Has(item.Key()) {
+		return
+	}
+
+	metrics.SolverOperationsPerIntent.Observe(float64(len(item.Value())))
+
+	d.watchers.closeIntent(item.Key())
+
+	// the store keeps the bundled status of an intent and only records when it expired
+	if _, ok := d.storedIntent(item.Key()); ok {
+		d.setIntentStatus(item.Key(), store.IntentStatusExpired)
+	}
+}
+
+func (d *DApp) SubscribeToIntentSolutions(intentID string) {
+	d.watchers.lock.Lock()
+	defer d.watchers.lock.Unlock()
+
+	d.cache.Set(intentID, []types.SolverOperationRaw{}, ttlcache.DefaultTTL)
+	d.watchers.trackIntent(intentID)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/FastLane-Labs/atlas-sdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// eip712Lock serializes the EIP-712 hashes of the Atlas SDK. They encode the chain id of the EIP-712 domain
+// shared by the chain config in place, so concurrent hashes race on it, and so do the reads of that chain id
+var eip712Lock sync.Mutex
+
+// hashSolverOperation returns the EIP-712 hash of the solver operation on the chain
+func hashSolverOperation(op *types.SolverOperation, chainID uint64) (common.Hash, error) {
+	eip712Lock.Lock()
+	defer eip712Lock.Unlock()
+
+	return op.Hash(chainID)
+}
+
+// hashUserOperation returns the hash of the user operation on the chain, the one dApp operations approve
+func hashUserOperation(op *types.UserOperation, chainID uint64) (common.Hash, error) {
+	eip712Lock.Lock()
+	defer eip712Lock.Unlock()
+
+	return op.Hash(utils.FlagTrustedOpHash(op.CallConfig), chainID)
+}
+
+// newUserOperationPartial returns the partial user operation sent as an intent, it embeds the user operation hash
+func newUserOperationPartial(chainID uint64, op *types.UserOperation, hints []common.Address) (*types.UserOperationPartialRaw, error) {
+	eip712Lock.Lock()
+	defer eip712Lock.Unlock()
+
+	return types.NewUserOperationPartialRaw(chainID, op, hints)
+}
+
+// eip712ChainID returns a copy of the chain id of the shared EIP-712 domain
+func eip712ChainID(domain *apitypes.TypedDataDomain) *math.HexOrDecimal256 {
+	eip712Lock.Lock()
+	defer eip712Lock.Unlock()
+
+	return (*math.HexOrDecimal256)(new(big.Int).Set((*big.Int)(domain.ChainId)))
+}
+
+// validateDAppOperationSignature checks that the dApp operation is signed by its from address
+func validateDAppOperationSignature(op *types.DAppOperation, chainID uint64) error {
+	eip712Lock.Lock()
+	defer eip712Lock.Unlock()
+
+	return op.ValidateSignature(chainID)
+}
@@ -2,248 +2,218 @@ package service
 
 import (
 	"context"
-	"crypto/tls"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"time"
+	"slices"
 
-	"github.com/FastLane-Labs/atlas-sdk-go/types"
-	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
-	"github.com/bloXroute-Labs/bloxroute-sdk-go/connection/ws"
-	"github.com/jellydator/ttlcache/v3"
-	"github.com/valyala/fastjson"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
+	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/bloXroute-Labs/bdn-operations-relay/config"
 	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/store"
 )
 
 // Intent is a service for interacting with the BDN intent network
 type Intent struct {
-	client              *sdk.Client
+	// conns are the connections to the BDN, the BDN accepts a single subscription per feed and connection
+	// so the n-th dApp and the n-th solver share the n-th connection. The intents subscriptions of the
+	// other dApp addresses of the solvers get the connections past those
+	conns               []*connection
 	cfg                 *config.Config
 	subscriptionManager *SubscriptionManager
-	cache               *ttlcache.Cache[string, []types.SolverOperationRaw]
+	ethClient           *ethclient.Client
+	blocks              *blockTracker
+	dApps               map[string]*DApp
+	solvers             map[string]*Solver
+	// store keeps the intents submitted through the relay and their solutions
+	store store.Store
 }
 
 // NewIntent creates a new Intent service
 func NewIntent(ctx context.Context, cfg *config.Config, subscriptionManager *SubscriptionManager) (*Intent, error) {
-	sdkConfig := &sdk.Config{
-		AuthHeader: cfg.BDN.AuthHeader,
-		Logger:     new(logger.Instance),
-	}
-
-	if cfg.BDN.GRPCURL != "" {
-		sdkConfig.GRPCDialOptions = []grpc.DialOption{
-			grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, "")),
-		}
-		sdkConfig.GRPCGatewayURL = cfg.BDN.GRPCURL
-	} else {
-		sdkConfig.WSDialOptions = &ws.DialOptions{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-			HandshakeTimeout: time.Minute,
-		}
-		sdkConfig.WSGatewayURL = cfg.BDN.WSURL
-	}
-
-	client, err := sdk.NewClient(ctx, sdkConfig)
+	conn, err := newConnection(ctx, &cfg.BDN)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create BDN client: %w", err)
+		return nil, fmt.Errorf("failed to connect to BDN: %w", err)
 	}
 
-	cache := ttlcache.New[string, []types.SolverOperationRaw](
-		ttlcache.WithTTL[string, []types.SolverOperationRaw](time.Minute),
-	)
-
-	go cache.Start()
-
-	return &Intent{
-		client:              client,
+	i := &Intent{
+		conns:               []*connection{conn},
 		cfg:                 cfg,
 		subscriptionManager: subscriptionManager,
-		cache:               cache,
-	}, nil
-}
-
-// Close closes the connection to the BDN
-func (i *Intent) Close() error {
-	return i.client.Close()
-}
-
-// SubmitIntent submits an intent to the BDN
-func (i *Intent) SubmitIntent(ctx context.Context, intent []byte) (string, error) {
-	params := &sdk.SubmitIntentParams{
-		DappAddress:      i.cfg.DAppAddress,
-		SenderPrivateKey: i.cfg.DAppPrivateKey,
-		Intent:           intent,
+		dApps:               make(map[string]*DApp),
+		solvers:             make(map[string]*Solver),
 	}
 
-	resp, err := i.client.SubmitIntent(ctx, params)
+	i.store, err = store.New(&cfg.Store)
 	if err != nil {
-		return "", fmt.Errorf("failed to submit intent: %w", err)
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to open intents store: %w", err)
 	}
 
-	var p fastjson.Parser
-	v, err := p.ParseBytes(*resp)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse message: %w", err)
+	if cfg.Atlas.EthRPCURL != "" {
+		i.ethClient, err = ethclient.DialContext(ctx, cfg.Atlas.EthRPCURL)
+		if err != nil {
+			_ = i.Close()
+			return nil, fmt.Errorf("failed to connect to the Atlas chain node: %w", err)
+		}
+
+		i.blocks, err = newBlockTracker(ctx, i.ethClient)
+		if err != nil {
+			_ = i.Close()
+			return nil, fmt.Errorf("failed to track Atlas chain blocks: %w", err)
+		}
+
+		subscriptionManager.trackBlocks(i.blocks, cfg.Atlas.ChainID)
 	}
 
-	return string(v.GetStringBytes("intent_id")), nil
-}
+	dApps := cfg.AllDApps()
+	solvers := cfg.AllSolvers()
 
-func (i *Intent) SubscribeToIntents(ctx context.Context) error {
-	logger.Debug("subscribing to intents")
+	for j, dAppCfg := range dApps {
+		dAppConn, err := i.connection(ctx, j)
+		if err != nil {
+			_ = i.Close()
+			return nil, fmt.Errorf("failed to connect to BDN for dApp %s: %w", dAppCfg.Name, err)
+		}
 
-	params := &sdk.IntentsParams{
-		SolverPrivateKey: i.cfg.SolverPrivateKey,
-		DappAddress:      i.cfg.DAppAddress,
+		dApp, err := newDApp(ctx, i, dAppConn, dAppCfg)
+		if err != nil {
+			_ = i.Close()
+			return nil, err
+		}
+
+		i.dApps[dAppCfg.Name] = dApp
 	}
 
-	err := i.client.OnIntents(ctx, params, func(ctx context.Context, err error, result *sdk.OnIntentsNotification) {
+	// the BDN takes a single intents subscription per connection, the dApp addresses of a solver
+	// past the first one subscribe on connections of their own
+	nextConn := max(len(dApps), len(solvers))
+
+	for j, solverCfg := range solvers {
+		solverConn, err := i.connection(ctx, j)
 		if err != nil {
-			logger.Error("error receiving intent", "error", err)
-			return
+			_ = i.Close()
+			return nil, fmt.Errorf("failed to connect to BDN for solver %s: %w", solverCfg.Name, err)
 		}
 
-		logger.Debug("received intent", "dapp_address", result.DappAddress, "sender_address", result.SenderAddress,
-			"intent_id", result.IntentID)
+		intentConns := []*connection{solverConn}
+		for k := 1; k < len(solverCfg.DAppAddresses); k++ {
+			intentConn, err := i.connection(ctx, nextConn)
+			if err != nil {
+				_ = i.Close()
+				return nil, fmt.Errorf("failed to connect to BDN for the intents of solver %s: %w", solverCfg.Name, err)
+			}
 
-		rawIntent := make([]byte, base64.StdEncoding.DecodedLen(len(result.Intent)))
-		_, err = base64.StdEncoding.Decode(rawIntent, result.Intent)
-		if err == nil {
-			result.Intent = rawIntent
+			intentConns = append(intentConns, intentConn)
+			nextConn++
 		}
 
-		i.subscriptionManager.Notify(result)
-	})
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to intents: %w", err)
+		solver, err := newSolver(ctx, i, solverConn, intentConns, solverCfg)
+		if err != nil {
+			_ = i.Close()
+			return nil, err
+		}
+
+		i.solvers[solverCfg.Name] = solver
 	}
 
-	return nil
+	return i, nil
 }
 
-// SubmitIntentSolution submits an intent solution to the BDN
-func (i *Intent) SubmitIntentSolution(ctx context.Context, intentID string, intent []byte) error {
-	params := &sdk.SubmitIntentSolutionParams{
-		SolverPrivateKey: i.cfg.SolverPrivateKey,
-		IntentID:         intentID,
-		IntentSolution:   intent,
-	}
+// connection returns the index-th connection to the BDN, connecting to the BDN when it does not exist yet
+func (i *Intent) connection(ctx context.Context, index int) (*connection, error) {
+	for len(i.conns) <= index {
+		conn, err := newConnection(ctx, &i.cfg.BDN)
+		if err != nil {
+			return nil, err
+		}
 
-	_, err := i.client.SubmitIntentSolution(ctx, params)
-	if err != nil {
-		return fmt.Errorf("failed to submit intent solution: %w", err)
+		i.conns = append(i.conns, conn)
 	}
 
-	return nil
+	return i.conns[index], nil
 }
 
-// GetIntentSolutions gets list of solutions for a specific intent
-func (i *Intent) GetIntentSolutions(ctx context.Context, intentID string) ([]types.SolverOperationRaw, error) {
-	// check if we have the solutions in cache
-	item := i.cache.Get(intentID)
-	if item != nil && len(item.Value()) != 0 {
-		logger.Debug("returning cached intent solutions", "intent_id", intentID)
-		return item.Value(), nil
+// Close closes the connections to the BDN and to the Atlas chain node, and the intents store
+func (i *Intent) Close() error {
+	if i.ethClient != nil {
+		i.ethClient.Close()
 	}
 
-	params := &sdk.GetSolutionsForIntentParams{
-		DAppOrSenderPrivateKey: i.cfg.DAppPrivateKey,
-		IntentID:               intentID,
+	for _, dApp := range i.dApps {
+		dApp.stopAuctions()
+		dApp.simulations.Stop()
 	}
 
-	resp, err := i.client.GetSolutionsForIntent(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get intent solutions: %w", err)
+	for _, solver := range i.solvers {
+		solver.deadlines.Stop()
 	}
 
-	var p fastjson.Parser
-	v, err := p.ParseBytes(*resp)
+	err := i.store.Close()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse message: %w", err)
+		logger.Warn("failed to close intents store", "error", err)
 	}
 
-	var result []types.SolverOperationRaw
-
-	for _, obj := range v.GetArray() {
-		intentSolution := obj.Get("intent_solution").GetStringBytes()
-
-		out := make([]byte, base64.StdEncoding.DecodedLen(len(intentSolution)))
-		n, err := base64.StdEncoding.Decode(out, intentSolution)
+	for _, conn := range i.conns[1:] {
+		err := conn.Close()
 		if err != nil {
-			logger.Error("failed to decode intent solution from base64", "error", err, "intent_solution", string(intentSolution))
-			continue
+			logger.Warn("failed to close BDN connection", "error", err)
 		}
-
-		var solverOperation *types.SolverOperationRaw
-		err = json.Unmarshal(out[:n], &solverOperation) // TODO use var p fastjson.Parser
-		if err != nil {
-			logger.Error("failed to unmarshal intent solution into SolverOperationRaw", "error", err,
-				"intent_solution", string(intentSolution))
-			continue
-		}
-
-		result = append(result, *solverOperation)
 	}
 
-	return result, nil
+	return i.conns[0].Close()
 }
 
-func (i *Intent) SubscribeToSolutions(ctx context.Context) error {
-	logger.Debug("subscribing to intent solutions")
-
-	params := &sdk.IntentSolutionsParams{
-		DappPrivateKey: i.cfg.DAppPrivateKey,
+// ConnectionState returns the state of the connection to the BDN, the least connected state of all connections
+func (i *Intent) ConnectionState() ConnectionState {
+	states := make([]ConnectionState, 0, len(i.conns))
+	for _, conn := range i.conns {
+		states = append(states, conn.State())
 	}
 
-	err := i.client.OnIntentSolutions(ctx, params, func(ctx context.Context, err error, result *sdk.OnIntentSolutionsNotification) {
-		if err != nil {
-			logger.Error("error receiving intent solution", "error", err)
-			return
-		}
+	switch {
+	case slices.Contains(states, ConnectionStateDisconnected):
+		return ConnectionStateDisconnected
+	case slices.Contains(states, ConnectionStateConnecting):
+		return ConnectionStateConnecting
+	default:
+		return ConnectionStateConnected
+	}
+}
 
-		logger.Debug("received intent solution", "intent_id", result.IntentID)
+// DApp returns the dApp configured under the name
+func (i *Intent) DApp(name string) (*DApp, bool) {
+	dApp, ok := i.dApps[name]
+	return dApp, ok
+}
 
-		item := i.cache.Get(result.IntentID)
-		if item == nil {
-			return
+// SubscribeToSolutions subscribes to the solutions of the intents of every dApp,
+// the subscriptions are restored after reconnecting
+func (i *Intent) SubscribeToSolutions(ctx context.Context) error {
+	for _, dApp := range i.dApps {
+		err := dApp.SubscribeToSolutions(ctx)
+		if err != nil {
+			return fmt.Errorf("dApp %s: %w", dApp.cfg.Name, err)
 		}
+	}
 
-		v := item.Value()
+	return nil
+}
 
-		out := make([]byte, base64.StdEncoding.DecodedLen(len(result.IntentSolution)))
-		n, err := base64.StdEncoding.Decode(out, result.IntentSolution)
-		if err != nil {
-			logger.Error("failed to decode intent solution from base64", "error", err, "intent_solution", string(result.IntentSolution))
-			return
-		}
+// Solver returns the solver configured under the name
+func (i *Intent) Solver(name string) (*Solver, bool) {
+	solver, ok := i.solvers[name]
+	return solver, ok
+}
 
-		var solverOperation *types.SolverOperationRaw
-		err = json.Unmarshal(out[:n], &solverOperation)
+// SubscribeToIntents subscribes every solver to the intents of the dApp,
+// the subscriptions are restored after reconnecting
+func (i *Intent) SubscribeToIntents(ctx context.Context) error {
+	for _, solver := range i.solvers {
+		err := solver.SubscribeToIntents(ctx)
 		if err != nil {
-			logger.Error("failed to unmarshal intent solution into SolverOperationRaw", "error", err,
-				"intent_solution", string(result.IntentSolution))
-			return
+			return fmt.Errorf("solver %s: %w", solver.cfg.Name, err)
 		}
-
-		v = append(v, *solverOperation)
-
-		i.cache.Set(result.IntentID, v, ttlcache.DefaultTTL)
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to intent solutions: %w", err)
 	}
 
 	return nil
 }
-
-func (i *Intent) SubscribeToIntentSolutions(intentID string) {
-	i.cache.Set(intentID, []types.SolverOperationRaw{}, ttlcache.DefaultTTL)
-}
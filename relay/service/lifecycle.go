@@ -0,0 +1,74 @@
+package service
+
+import (
+	"time"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/store"
+)
+
+// IntentLifecycle is the lifecycle of an intent submitted through the relay
+type IntentLifecycle struct {
+	IntentID string             `json:"intent_id"`
+	Status   store.IntentStatus `json:"status"`
+	// SubmittedAt is when the BDN accepted the intent
+	SubmittedAt       time.Time  `json:"submitted_at"`
+	SolutionsReceived int        `json:"solutions_received"`
+	FirstSolutionAt   *time.Time `json:"first_solution_at,omitempty"`
+	LastSolutionAt    *time.Time `json:"last_solution_at,omitempty"`
+	Expired           bool       `json:"expired"`
+	ExpiredAt         *time.Time `json:"expired_at,omitempty"`
+	Bundled           bool       `json:"bundled"`
+	BundledAt         *time.Time `json:"bundled_at,omitempty"`
+	// AuctionClosedAt is when the auction of the intent closed, omitted while it is open or when the dApp does not auction its intents
+	AuctionClosedAt *time.Time `json:"auction_closed_at,omitempty"`
+}
+
+// IntentLifecycle returns the lifecycle of an intent the dApp submitted through the relay,
+// ErrIntentNotFound when it is unknown or past the retention period of the store
+func (d *DApp) IntentLifecycle(intentID string) (*IntentLifecycle, error) {
+	intent, ok := d.storedIntent(intentID)
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+
+	// the live window of an intent still open when the relay restarted never ends in the cache of this process,
+	// it is derived from the time the intent was submitted
+	if intent.ExpiredAt.IsZero() && !d.cache.Has(intent.ID) && time.Since(intent.SubmittedAt) > intentTrackingTTL {
+		intent.ExpiredAt = intent.SubmittedAt.Add(intentTrackingTTL)
+		if intent.Status == store.IntentStatusOpen {
+			intent.Status = store.IntentStatusExpired
+		}
+	}
+
+	lifecycle := &IntentLifecycle{
+		IntentID:          intent.ID,
+		Status:            intent.Status,
+		SubmittedAt:       intent.SubmittedAt,
+		SolutionsReceived: len(intent.Solutions),
+		Expired:           !intent.ExpiredAt.IsZero(),
+		ExpiredAt:         timeOrNil(intent.ExpiredAt),
+		Bundled:           !intent.BundledAt.IsZero(),
+		BundledAt:         timeOrNil(intent.BundledAt),
+	}
+
+	if intent.Auction != nil {
+		lifecycle.AuctionClosedAt = &intent.Auction.ClosedAt
+	}
+
+	// the solutions are stored in the order they were received
+	if len(intent.Solutions) != 0 {
+		lifecycle.FirstSolutionAt = &intent.Solutions[0].ReceivedAt
+		lifecycle.LastSolutionAt = &intent.Solutions[len(intent.Solutions)-1].ReceivedAt
+	}
+
+	return lifecycle, nil
+}
+
+// timeOrNil returns nil for the zero time, so it is omitted from the JSON
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
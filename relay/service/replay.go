@@ -0,0 +1,167 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+	"github.com/google/uuid"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+)
+
+var (
+	// ErrReplayDisabled is returned for the subscriptions resuming from a sequence number when the replay buffer is disabled
+	ErrReplayDisabled = errors.New("replay buffer is disabled")
+	// ErrReplayUnsupported is returned for the subscriptions other than intent subscriptions resuming from a sequence number
+	ErrReplayUnsupported = errors.New("only intent subscriptions can resume from a sequence number")
+	// ErrReplayOverflow is returned when the missed intents of the subscription do not fit in its buffer
+	ErrReplayOverflow = errors.New("missed intents exceed the subscription buffer")
+)
+
+// IntentNotification is the notification delivered to intent subscriptions
+type IntentNotification struct {
+	*sdk.OnIntentsNotification
+	// Sequence increases by one with each intent the relay receives, across all the solvers,
+	// so a subscription only sees gaps for the intents of other solvers or filtered out
+	Sequence uint64 `json:"sequence"`
+	// Epoch identifies the run of the relay which numbered the intent, the sequences restart with every run
+	Epoch string `json:"epoch"`
+}
+
+// replayEntry is an intent notification kept for the subscriptions resuming from a sequence number
+type replayEntry struct {
+	solver       string
+	notification *IntentNotification
+}
+
+// replayBuffer numbers the intent notifications and keeps the most recent ones in a ring
+type replayBuffer struct {
+	// lock is held while an intent is numbered and delivered, so the subscriptions get the intents in sequence order
+	lock    sync.Mutex
+	entries []replayEntry
+	// next is the sequence number of the next intent, the sequences start at 1
+	next uint64
+	// epoch identifies the run of the relay, a sequence number of another run designates another intent
+	epoch string
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{
+		entries: make([]replayEntry, size),
+		next:    1,
+		epoch:   uuid.New().String(),
+	}
+}
+
+// add numbers the intent of the solver and keeps it in the ring, dropping the oldest intent once full
+func (b *replayBuffer) add(solver string, n *sdk.OnIntentsNotification) *IntentNotification {
+	notification := &IntentNotification{
+		OnIntentsNotification: n,
+		Sequence:              b.next,
+		Epoch:                 b.epoch,
+	}
+	b.next++
+
+	if len(b.entries) > 0 {
+		b.entries[notification.Sequence%uint64(len(b.entries))] = replayEntry{
+			solver:       solver,
+			notification: notification,
+		}
+	}
+
+	return notification
+}
+
+// since returns the kept intents from the sequence number of the epoch on, oldest first. truncated reports that
+// intents after fromSequence are no longer kept, or that fromSequence belongs to another run of the relay,
+// all the kept intents are returned then. An empty fromEpoch is taken as the current epoch
+func (b *replayBuffer) since(fromEpoch string, fromSequence uint64) (entries []replayEntry, truncated bool) {
+	oldest := uint64(1)
+	if size := uint64(len(b.entries)); b.next > size {
+		oldest = b.next - size
+	}
+
+	switch {
+	case fromEpoch != "" && fromEpoch != b.epoch:
+		fromSequence, truncated = oldest, true
+	case fromSequence > b.next:
+		fromSequence, truncated = oldest, true
+	case fromSequence < oldest:
+		fromSequence, truncated = oldest, true
+	}
+
+	for seq := fromSequence; seq < b.next; seq++ {
+		entries = append(entries, b.entries[seq%uint64(len(b.entries))])
+	}
+
+	return entries, truncated
+}
+
+// replay queues the kept intents of the subscription from the sequence number of the epoch on to its buffer
+func (s *SubscriptionManager) replay(subscription *Subscription, fromEpoch string, fromSequence uint64) error {
+	if subscription.Type != SubscriptionTypeIntent {
+		return ErrReplayUnsupported
+	}
+
+	if s.cfg.ReplayBufferSize == 0 {
+		return ErrReplayDisabled
+	}
+
+	s.replays.lock.Lock()
+	defer s.replays.lock.Unlock()
+
+	entries, truncated := s.replays.since(fromEpoch, fromSequence)
+	subscription.ReplayTruncated = truncated
+	head := s.head()
+
+	missed := make([]*IntentNotification, 0, len(entries))
+	for _, entry := range entries {
+		if entry.solver == subscription.Filter.Solver &&
+			subscription.Filter.matches(entry.notification, intentUserOperation(entry.notification), head) {
+			missed = append(missed, entry.notification)
+		}
+	}
+
+	// the slow consumer policy does not apply to the replay, a missed intent dropped from it would be lost silently
+	if len(missed) > cap(subscription.NotificationChannel)-len(subscription.NotificationChannel) {
+		return ErrReplayOverflow
+	}
+
+	for _, n := range missed {
+		select {
+		case subscription.NotificationChannel <- n:
+			subscription.Replayed++
+		default:
+			return ErrReplayOverflow
+		}
+	}
+
+	return nil
+}
+
+// intentUserOperation returns a function decoding the user operation of the intent at most once,
+// it returns nil if the intent cannot be decoded
+func intentUserOperation(n *IntentNotification) func() *types.UserOperationPartialRaw {
+	var (
+		userOp  *types.UserOperationPartialRaw
+		decoded bool
+	)
+
+	return func() *types.UserOperationPartialRaw {
+		if decoded {
+			return userOp
+		}
+		decoded = true
+
+		err := json.Unmarshal(n.Intent, &userOp)
+		if err != nil {
+			logger.Debug("failed to decode intent for filtering", "intent_id", n.IntentID, "error", err)
+			userOp = nil
+		}
+
+		return userOp
+	}
+}
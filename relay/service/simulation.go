@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/contract/simulator"
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/jellydator/ttlcache/v3"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+)
+
+const (
+	simSolverCallMethod   = "simSolverCall"
+	simulationConcurrency = 8
+)
+
+// SimulationResult is the outcome of the simSolverCall of the Atlas simulator for a solver operation
+type SimulationResult struct {
+	Success bool `json:"success"`
+	// Result is the Atlas simulator result code
+	Result uint8 `json:"result"`
+	// SolverOutcome is the bitmap of the Atlas solver outcome
+	SolverOutcome *hexutil.Big `json:"solver_outcome,omitempty"`
+	// Error is set when the simulation could not be run
+	Error string `json:"error,omitempty"`
+}
+
+// SimulatedSolverOperation is a solver operation with the result of its simulation, if it was simulated
+type SimulatedSolverOperation struct {
+	types.SolverOperationRaw
+	Simulation *SimulationResult `json:"simulation,omitempty"`
+}
+
+// simulationKey identifies the simulation of a solver operation at a block, the result of the same
+// solver operation changes with the state of the chain
+type simulationKey struct {
+	hash        common.Hash
+	blockNumber uint64
+}
+
+// SimulateSolverOperations simulates the solver operations of an intent submitted through the relay
+// against the Atlas simulator contract and attaches the results. The operations are returned unchanged when
+// simulation is disabled or the user operation is unknown. Operations that fail the simulation are dropped
+// in filter mode, operations that could not be simulated are always kept. limit caps the number of operations
+// returned, all of them are returned when 0. Only filter mode simulates the operations past the limit
+func (d *DApp) SimulateSolverOperations(ctx context.Context, intentID string, ops []types.SolverOperationRaw, limit int) []SimulatedSolverOperation {
+	filter := d.intent.cfg.Atlas.SimulationMode == config.SimulationModeFilter
+
+	// in filter mode the operations past the limit replace the ones failing the simulation, they are all simulated
+	if !filter && limit > 0 && len(ops) > limit {
+		ops = ops[:limit]
+	}
+
+	result := make([]SimulatedSolverOperation, len(ops))
+	for j := range ops {
+		result[j].SolverOperationRaw = ops[j]
+	}
+
+	if d.intent.cfg.Atlas.SimulationMode == config.SimulationModeDisabled || d.ethClient == nil {
+		return result
+	}
+
+	entry, ok := d.userOperation(intentID)
+	if !ok {
+		return result
+	}
+
+	blockNumber, _ := d.blocks.BlockNumber()
+
+	eg, gCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(simulationConcurrency)
+
+	for j := range result {
+		eg.Go(func() error {
+			result[j].Simulation = d.simulateSolverOperation(gCtx, entry, &result[j].SolverOperationRaw, blockNumber)
+			return nil
+		})
+	}
+
+	_ = eg.Wait()
+
+	if !filter {
+		return result
+	}
+
+	filtered := result[:0]
+	for _, op := range result {
+		if op.Simulation.Error == "" && !op.Simulation.Success {
+			continue
+		}
+
+		filtered = append(filtered, op)
+	}
+
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+
+	return filtered
+}
+
+// simulateSolverOperation returns the simulation of the solver operation as of the block. The results are cached,
+// so the dApp polling the solver operations of an intent simulates each of them once per block
+func (d *DApp) simulateSolverOperation(ctx context.Context, entry *userOperationEntry, op *types.SolverOperationRaw, blockNumber uint64) *SimulationResult {
+	hash, hashErr := d.solverOperationHash(op)
+	key := simulationKey{hash: hash, blockNumber: blockNumber}
+
+	if hashErr == nil {
+		if item := d.simulations.Get(key); item != nil {
+			return item.Value()
+		}
+	}
+
+	res, err := d.simSolverCall(ctx, entry, op)
+	if err != nil {
+		// the operations that could not be simulated are simulated again by the next query
+		logger.Debug("failed to simulate solver operation", "error", err, "from", op.From)
+		return &SimulationResult{Error: err.Error()}
+	}
+
+	if hashErr == nil {
+		d.simulations.Set(key, res, ttlcache.DefaultTTL)
+	}
+
+	return res
+}
+
+// simSolverCall runs the simSolverCall method of the Atlas simulator with eth_call
+func (d *DApp) simSolverCall(ctx context.Context, entry *userOperationEntry, op *types.SolverOperationRaw) (*SimulationResult, error) {
+	contracts, err := atlasContracts(entry.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	simulatorABI, err := simulator.SimulatorMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse simulator ABI: %w", err)
+	}
+
+	userOp := entry.userOperation
+
+	userOpHash, err := hashUserOperation(userOp, entry.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash user operation: %w", err)
+	}
+
+	solverOp := op.Decode()
+	solverOp.Sanitize()
+
+	dAppOp, err := d.signDAppOperation(ctx, entry, userOpHash, types.SolverOperations{solverOp}, common.Address{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	dAppOp.Sanitize()
+
+	data, err := simulatorABI.Pack(simSolverCallMethod, *userOp, *solverOp, *dAppOp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack simulator call: %w", err)
+	}
+
+	out, err := d.ethClient.CallContract(ctx, ethereum.CallMsg{
+		From: dAppOp.From,
+		To:   &contracts.Simulator,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("simulator call failed: %w", err)
+	}
+
+	values, err := simulatorABI.Unpack(simSolverCallMethod, out)
+	if err != nil || len(values) != 3 {
+		return nil, fmt.Errorf("failed to unpack simulator result: %v", err)
+	}
+
+	success, _ := values[0].(bool)
+	code, _ := values[1].(uint8)
+	outcome, _ := values[2].(*big.Int)
+
+	return &SimulationResult{
+		Success:       success,
+		Result:        code,
+		SolverOutcome: (*hexutil.Big)(outcome),
+	}, nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/metrics"
+)
+
+// dropOldestAttempts bounds the retries of the drop-oldest policy when other notifications refill the buffer first
+const dropOldestAttempts = 3
+
+// subscriptionStats tracks how a subscription keeps up with its notifications
+type subscriptionStats struct {
+	dropped atomic.Uint64
+	// overflowedAt is when the buffer overflowed in unix nanoseconds, zero once the client drained it
+	overflowedAt atomic.Int64
+}
+
+// SubscriptionStatus is the periodic status notification of a subscription
+type SubscriptionStatus struct {
+	SubscriptionID string `json:"subscription_id"`
+	// Pending is the number of notifications buffered for the client
+	Pending    int `json:"pending"`
+	BufferSize int `json:"buffer_size"`
+	// Dropped is the number of notifications dropped since the subscription started
+	Dropped            uint64                    `json:"dropped"`
+	SlowConsumerPolicy config.SlowConsumerPolicy `json:"slow_consumer_policy"`
+}
+
+// Delivered records that a notification was sent to the client, the client caught up once its buffer is empty
+func (s *Subscription) Delivered() {
+	if len(s.NotificationChannel) == 0 {
+		s.stats.overflowedAt.Store(0)
+	}
+}
+
+// Dropped returns the number of notifications of the subscription dropped because its buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.stats.dropped.Load()
+}
+
+// drop counts a notification dropped for the subscription
+func (s *Subscription) drop() {
+	s.stats.dropped.Add(1)
+	metrics.NotificationsDropped.WithLabelValues(string(s.Type)).Inc()
+}
+
+// deliver buffers the notification for the subscription, applying the slow consumer policy when its buffer is full.
+// It returns false when the client must be evicted
+func (s *SubscriptionManager) deliver(subscription *Subscription, n interface{}) bool {
+	select {
+	case subscription.NotificationChannel <- n:
+		return true
+	default:
+	}
+
+	subscription.stats.overflowedAt.CompareAndSwap(0, time.Now().UnixNano())
+
+	switch s.cfg.Policy() {
+	case config.SlowConsumerPolicyDisconnect:
+		return false
+	case config.SlowConsumerPolicyDropOldest:
+		for i := 0; i < dropOldestAttempts; i++ {
+			select {
+			case <-subscription.NotificationChannel:
+				subscription.drop()
+			default:
+			}
+
+			select {
+			case subscription.NotificationChannel <- n:
+				return true
+			default:
+			}
+		}
+	}
+
+	subscription.drop()
+
+	return true
+}
+
+// evict closes the connection of a client that did not keep up with its notifications
+func (s *SubscriptionManager) evict(connectionID, reason string) {
+	s.lock.RLock()
+	conn, ok := s.connections[connectionID]
+	s.lock.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	logger.Warn("evicting slow client", "reason", reason, "connection_id", connectionID, "caller", conn.info.RemoteAddress,
+		"identity", conn.info.Identity)
+	metrics.ClientsEvicted.Inc()
+
+	conn.close()
+}
+
+// run sends the status notifications of the subscriptions and evicts the clients that fell behind
+func (s *SubscriptionManager) run() {
+	var status, lag <-chan time.Time
+
+	if s.cfg.StatusInterval > 0 {
+		ticker := time.NewTicker(s.cfg.StatusInterval)
+		defer ticker.Stop()
+		status = ticker.C
+	}
+
+	if s.cfg.MaxLag > 0 {
+		ticker := time.NewTicker(s.cfg.MaxLag / 2)
+		defer ticker.Stop()
+		lag = ticker.C
+	}
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-status:
+			s.notifyStatus()
+		case <-lag:
+			s.evictLagging()
+		}
+	}
+}
+
+// notifyStatus sends its status to each subscription, the status is subject to the slow consumer policy
+func (s *SubscriptionManager) notifyStatus() {
+	var evicted []string
+
+	s.lock.RLock()
+	s.intentsSubscriptions.Range(func(_ string, value []Subscription) bool {
+		for i := range value {
+			subscription := &value[i]
+
+			status := &SubscriptionStatus{
+				SubscriptionID:     subscription.ID,
+				Pending:            len(subscription.NotificationChannel),
+				BufferSize:         cap(subscription.NotificationChannel),
+				Dropped:            subscription.Dropped(),
+				SlowConsumerPolicy: s.cfg.Policy(),
+			}
+
+			if !s.deliver(subscription, status) {
+				evicted = append(evicted, subscription.connectionID)
+			}
+		}
+
+		return true
+	})
+	s.lock.RUnlock()
+
+	for _, connectionID := range evicted {
+		s.evict(connectionID, "notification buffer full")
+	}
+}
+
+// evictLagging evicts the clients with a subscription whose buffer overflowed and was not drained within the max lag
+func (s *SubscriptionManager) evictLagging() {
+	var evicted []string
+
+	s.lock.RLock()
+	s.intentsSubscriptions.Range(func(connectionID string, value []Subscription) bool {
+		for i := range value {
+			overflowedAt := value[i].stats.overflowedAt.Load()
+			if overflowedAt != 0 && time.Since(time.Unix(0, overflowedAt)) > s.cfg.MaxLag {
+				evicted = append(evicted, connectionID)
+				break
+			}
+		}
+
+		return true
+	})
+	s.lock.RUnlock()
+
+	for _, connectionID := range evicted {
+		s.evict(connectionID, "fell behind")
+	}
+}
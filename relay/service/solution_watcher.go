@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/metrics"
+)
+
+const (
+	watcherChannelSize = 100
+	// watcherSubscriptionType labels the metrics of the dApp solution watchers
+	watcherSubscriptionType = "solutions"
+)
+
+// SolutionWatcher receives the solutions of an intent as they arrive
+type SolutionWatcher struct {
+	// Updates delivers every new solution, it is closed when the intent expires
+	Updates <-chan types.SolverOperationRaw
+	ch      chan types.SolverOperationRaw
+	// dropped counts the solutions not delivered because the channel was full
+	dropped atomic.Uint64
+}
+
+// Dropped returns the number of solutions the watcher missed because it did not keep up,
+// the missed solutions are still returned by GetIntentSolutions
+func (w *SolutionWatcher) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Status returns the status of the subscription fed by the watcher, the solutions of a full watcher are dropped
+func (w *SolutionWatcher) Status(subscriptionID string) *SubscriptionStatus {
+	return &SubscriptionStatus{
+		SubscriptionID:     subscriptionID,
+		Pending:            len(w.ch),
+		BufferSize:         cap(w.ch),
+		Dropped:            w.Dropped(),
+		SlowConsumerPolicy: config.SlowConsumerPolicyDropNewest,
+	}
+}
+
+// solutionWatchers fans out the solutions received for an intent to the clients waiting for them
+type solutionWatchers struct {
+	lock     sync.Mutex
+	watchers map[string]map[*SolutionWatcher]struct{}
+	// received are the EIP-712 hashes of the solutions received for the tracked intents, by intent id
+	received map[string]map[common.Hash]struct{}
+}
+
+func newSolutionWatchers() *solutionWatchers {
+	return &solutionWatchers{
+		watchers: make(map[string]map[*SolutionWatcher]struct{}),
+		received: make(map[string]map[common.Hash]struct{}),
+	}
+}
+
+// trackIntent forgets the solutions received for the intent, must be called with the lock held
+func (s *solutionWatchers) trackIntent(intentID string) {
+	s.received[intentID] = make(map[common.Hash]struct{})
+}
+
+// markReceived records the solution hash for the intent and reports whether it was not received before,
+// must be called with the lock held
+func (s *solutionWatchers) markReceived(intentID string, hash common.Hash) bool {
+	if s.received[intentID] == nil {
+		s.received[intentID] = make(map[common.Hash]struct{})
+	}
+
+	if _, ok := s.received[intentID][hash]; ok {
+		return false
+	}
+
+	s.received[intentID][hash] = struct{}{}
+
+	return true
+}
+
+// add registers a new watcher for the intent, must be called with the lock held
+func (s *solutionWatchers) add(intentID string) *SolutionWatcher {
+	ch := make(chan types.SolverOperationRaw, watcherChannelSize)
+	w := &SolutionWatcher{
+		Updates: ch,
+		ch:      ch,
+	}
+	metrics.ActiveSubscriptions.WithLabelValues(watcherSubscriptionType).Inc()
+
+	if s.watchers[intentID] == nil {
+		s.watchers[intentID] = make(map[*SolutionWatcher]struct{})
+	}
+	s.watchers[intentID][w] = struct{}{}
+
+	return w
+}
+
+// remove unregisters the watcher and closes its channel
+func (s *solutionWatchers) remove(intentID string, w *SolutionWatcher) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.watchers[intentID][w]; !ok {
+		return
+	}
+
+	close(w.ch)
+	delete(s.watchers[intentID], w)
+	metrics.ActiveSubscriptions.WithLabelValues(watcherSubscriptionType).Dec()
+
+	if len(s.watchers[intentID]) == 0 {
+		delete(s.watchers, intentID)
+	}
+}
+
+// notify sends the solution to all watchers of the intent, must be called with the lock held.
+// The watchers that are full miss the solution and count it as dropped
+func (s *solutionWatchers) notify(intentID string, solution types.SolverOperationRaw) {
+	for w := range s.watchers[intentID] {
+		select {
+		case w.ch <- solution:
+		default:
+			logger.Warn("solution watcher channel is full, dropping solution", "intent_id", intentID)
+			w.dropped.Add(1)
+			metrics.NotificationsDropped.WithLabelValues(watcherSubscriptionType).Inc()
+		}
+	}
+}
+
+// closeIntent closes all watchers of the intent and forgets its solutions, must be called with the lock held
+func (s *solutionWatchers) closeIntent(intentID string) {
+	for w := range s.watchers[intentID] {
+		close(w.ch)
+		metrics.ActiveSubscriptions.WithLabelValues(watcherSubscriptionType).Dec()
+	}
+
+	delete(s.watchers, intentID)
+	delete(s.received, intentID)
+}
+
+// WatchIntentSolutions returns the solutions received so far for the intent and a watcher
+// delivering every new one as it arrives. The watcher is closed when the intent expires.
+// ok is false when the relay does not track the intent, stop must be called once the caller is done
+func (d *DApp) WatchIntentSolutions(intentID string) (solutions []types.SolverOperationRaw, watcher *SolutionWatcher, ok bool, stop func()) {
+	d.watchers.lock.Lock()
+	defer d.watchers.lock.Unlock()
+
+	item := d.cache.Get(intentID)
+	if item == nil {
+		return nil, nil, false, func() {}
+	}
+
+	w := d.watchers.add(intentID)
+	solutions = append(solutions, item.Value()...)
+
+	return solutions, w, true, func() { d.watchers.remove(intentID, w) }
+}
+
+// WaitForIntentSolutions blocks until at least minSolutions solutions were received for the intent
+// or ctx is done, and returns the solutions received so far
+func (d *DApp) WaitForIntentSolutions(ctx context.Context, intentID string, minSolutions int) ([]types.SolverOperationRaw, error) {
+	solutions, watcher, ok, stop := d.WatchIntentSolutions(intentID)
+	defer stop()
+
+	if !ok {
+		// the intent was not submitted through this relay, nothing to wait for
+		return d.GetIntentSolutions(ctx, intentID)
+	}
+
+	// the solutions the watcher missed count towards minSolutions, they are read back from the cache once the wait is over
+	for len(solutions)+int(watcher.Dropped()) < minSolutions {
+		select {
+		case <-ctx.Done():
+			return d.filterSolverOperations(d.watchedSolutions(intentID, watcher, solutions)), nil
+		case solution, open := <-watcher.Updates:
+			if !open {
+				return d.filterSolverOperations(d.watchedSolutions(intentID, watcher, solutions)), nil
+			}
+
+			solutions = append(solutions, solution)
+		}
+	}
+
+	return d.filterSolverOperations(d.watchedSolutions(intentID, watcher, solutions)), nil
+}
+
+// watchedSolutions returns the solutions delivered to the watcher, or all the solutions received for the intent
+// when the watcher dropped some of them
+func (d *DApp) watchedSolutions(intentID string, w *SolutionWatcher, solutions []types.SolverOperationRaw) []types.SolverOperationRaw {
+	if w.Dropped() == 0 {
+		return solutions
+	}
+
+	d.watchers.lock.Lock()
+	defer d.watchers.lock.Unlock()
+
+	item := d.cache.Get(intentID)
+	if item == nil {
+		return solutions
+	}
+
+	return append([]types.SolverOperationRaw(nil), item.Value()...)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/valyala/fastjson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/metrics"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/signer"
+)
+
+var (
+	ErrUnknownIntent = errors.New("BDN does not know the intent")
+	ErrIntentExpired = errors.New("intent expired")
+	ErrBDNRejected   = errors.New("BDN rejected the intent solution")
+)
+
+// receivedIntentRetention is how long the deadlines of the intents delivered to a solver are remembered,
+// the solutions of an older intent are left to the BDN to accept or reject
+const receivedIntentRetention = 10 * intentTrackingTTL
+
+// Solver submits solver operations to the BDN under its own key
+// and receives the intents delivered to it apart from the other solvers
+type Solver struct {
+	intent *Intent
+	conn   *connection
+	cfg    config.SolverConfig
+	signer signer.Signer
+	// intentConns are the connections of the intents subscriptions, one per dApp address of the solver
+	intentConns []*connection
+	// deadlines are the deadline blocks of the intents delivered to the solver, by intent id. The intents whose
+	// deadline is unknown or not on the chain of the Atlas chain node are not recorded
+	deadlines *ttlcache.Cache[string, uint64]
+}
+
+// SubmittedSolution is the acknowledgement of an intent solution by the BDN
+type SubmittedSolution struct {
+	SolutionID string
+	// AcceptedAt is when the relay received the acknowledgement
+	AcceptedAt time.Time
+}
+
+func newSolver(ctx context.Context, intent *Intent, conn *connection, intentConns []*connection, cfg config.SolverConfig) (*Solver, error) {
+	s, err := signer.New(ctx, cfg.PrivateKey, &cfg.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer of solver %s: %w", cfg.Name, err)
+	}
+
+	solver := &Solver{
+		intent:      intent,
+		conn:        conn,
+		intentConns: intentConns,
+		cfg:         cfg,
+		signer:      s,
+		deadlines: ttlcache.New[string, uint64](
+			ttlcache.WithTTL[string, uint64](receivedIntentRetention),
+		),
+	}
+
+	go solver.deadlines.Start()
+
+	return solver, nil
+}
+
+// Name returns the name of the solver
+func (s *Solver) Name() string {
+	return s.cfg.Name
+}
+
+// Address returns the address the solver signs its solver operations with
+func (s *Solver) Address() common.Address {
+	return s.signer.Address()
+}
+
+// SubscribeToIntents subscribes to the intents of the dApps of the solver, each dApp address on its own connection.
+// The subscriptions are restored after reconnecting
+func (s *Solver) SubscribeToIntents(ctx context.Context) error {
+	logger.Debug("subscribing to intents", "solver", s.cfg.Name, "dapp_addresses", s.cfg.DAppAddresses)
+
+	address := s.signer.Address().Hex()
+
+	// the signature does not change, it is reused by every subscription and when they are restored
+	hash, signature, err := signer.SignPayload(ctx, s.signer, []byte(address))
+	if err != nil {
+		return fmt.Errorf("failed to sign intents subscription: %w", err)
+	}
+
+	for j, dAppAddress := range s.cfg.DAppAddresses {
+		params := &sdk.IntentsParams{
+			DappAddress:   dAppAddress,
+			SolverAddress: address,
+			Hash:          hash,
+			Signature:     signature,
+		}
+
+		err = s.intentConns[j].Subscribe(ctx, func(ctx context.Context, client *sdk.Client, onError func(error)) error {
+			return client.OnIntents(ctx, params, func(ctx context.Context, err error, result *sdk.OnIntentsNotification) {
+				if err != nil {
+					logger.Error("error receiving intent", "error", err, "solver", s.cfg.Name, "dapp_address", dAppAddress)
+					onError(err)
+					return
+				}
+
+				s.onIntent(result)
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to the intents of dApp %s: %w", dAppAddress, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Solver) onIntent(result *sdk.OnIntentsNotification) {
+	logger.Debug("received intent", "dapp_address", result.DappAddress, "sender_address", result.SenderAddress,
+		"intent_id", result.IntentID, "solver", s.cfg.Name)
+
+	metrics.IntentsReceived.Inc()
+
+	rawIntent := make([]byte, base64.StdEncoding.DecodedLen(len(result.Intent)))
+	n, err := base64.StdEncoding.Decode(rawIntent, result.Intent)
+	if err == nil {
+		result.Intent = rawIntent[:n]
+	}
+
+	// the deadline is recorded before the intent is delivered so it applies to every solution of the clients
+	if deadline := s.intentDeadline(result.Intent); deadline != 0 {
+		s.deadlines.Set(result.IntentID, deadline, ttlcache.DefaultTTL)
+	}
+
+	s.intent.subscriptionManager.NotifyIntent(s.cfg.Name, result)
+}
+
+// intentDeadline returns the deadline block of the user operation of an intent,
+// zero when it cannot be checked against the blocks of the Atlas chain node
+func (s *Solver) intentDeadline(intent []byte) uint64 {
+	var userOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent, &userOp); err != nil {
+		return 0
+	}
+
+	if userOp.ChainId == nil || !userOp.ChainId.ToInt().IsUint64() || userOp.ChainId.ToInt().Uint64() != s.intent.cfg.Atlas.ChainID {
+		return 0
+	}
+
+	if userOp.Deadline == nil || !userOp.Deadline.ToInt().IsUint64() {
+		return 0
+	}
+
+	return userOp.Deadline.ToInt().Uint64()
+}
+
+// checkIntent returns ErrIntentExpired when the intent is past its deadline block. Any other intent,
+// including the ones the solver did not receive through the relay, is left to the BDN
+func (s *Solver) checkIntent(intentID string) error {
+	item := s.deadlines.Get(intentID, ttlcache.WithDisableTouchOnHit[string, uint64]())
+	if item == nil {
+		return nil
+	}
+
+	if blockNumber, ok := s.intent.blocks.BlockNumber(); ok && item.Value() < blockNumber {
+		return ErrIntentExpired
+	}
+
+	return nil
+}
+
+// SubmitIntentSolution submits an intent solution to the BDN. It returns ErrIntentExpired without submitting it
+// when the intent is past its deadline block. It wraps ErrUnknownIntent when the BDN does not know the intent
+// and ErrBDNRejected when the BDN refuses the solution for another reason
+func (s *Solver) SubmitIntentSolution(ctx context.Context, intentID string, intent []byte) (*SubmittedSolution, error) {
+	if err := s.checkIntent(intentID); err != nil {
+		return nil, err
+	}
+
+	hash, signature, err := signer.SignPayload(ctx, s.signer, intent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign intent solution: %w", err)
+	}
+
+	params := &sdk.SubmitIntentSolutionParams{
+		SolverAddress:  s.signer.Address().Hex(),
+		IntentID:       intentID,
+		IntentSolution: intent,
+		Hash:           hash,
+		Signature:      signature,
+	}
+
+	client, err := s.conn.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	response, err := client.SubmitIntentSolution(ctx, params)
+	metrics.ObserveBDNRequest("SubmitIntentSolution", start, err)
+	metrics.SolverOperationsSubmitted.WithLabelValues(metrics.Status(err)).Inc()
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownIntent, err)
+		}
+
+		if isBDNRejection(err) {
+			return nil, fmt.Errorf("%w: %v", ErrBDNRejected, err)
+		}
+
+		return nil, fmt.Errorf("failed to submit intent solution: %w", err)
+	}
+
+	solution := &SubmittedSolution{
+		AcceptedAt: time.Now(),
+	}
+
+	if response != nil {
+		var p fastjson.Parser
+		v, err := p.ParseBytes(*response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse intent solution response: %w", err)
+		}
+
+		solution.SolutionID = string(v.GetStringBytes("solution_id"))
+	}
+
+	return solution, nil
+}
+
+// isBDNRejection reports whether err is an error answered by the gateway, as opposed to a failure to reach it
+func isBDNRejection(err error) bool {
+	var rpcErr *sdk.RPCError
+	if errors.As(err, &rpcErr) {
+		return true
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.Unknown:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/FastLane-Labs/atlas-sdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+)
+
+var (
+	ErrMalformedSolverOperation = errors.New("malformed solver operation")
+	ErrInvalidSolverSignature   = errors.New("invalid solver operation signature")
+)
+
+// DecodeSolverOperation decodes an Atlas solver operation and verifies that its EIP-712 signature,
+// computed against the domain of the configured Atlas chain, was produced by the from address.
+// It returns the decoded operation and its EIP-712 hash
+func (i *Intent) DecodeSolverOperation(data []byte) (*types.SolverOperationRaw, common.Hash, error) {
+	var raw *types.SolverOperationRaw
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("%w: %v", ErrMalformedSolverOperation, err)
+	}
+
+	if raw == nil {
+		return nil, common.Hash{}, fmt.Errorf("%w: empty solver operation", ErrMalformedSolverOperation)
+	}
+
+	if raw.From == (common.Address{}) {
+		return nil, common.Hash{}, fmt.Errorf("%w: from is required", ErrMalformedSolverOperation)
+	}
+
+	if len(raw.Signature) != crypto.SignatureLength {
+		return nil, common.Hash{}, fmt.Errorf("%w: signature must be %d bytes long", ErrMalformedSolverOperation, crypto.SignatureLength)
+	}
+
+	hash, err := hashSolverOperation(raw.Decode(), i.cfg.Atlas.ChainID)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("%w: failed to compute EIP-712 hash: %v", ErrMalformedSolverOperation, err)
+	}
+
+	signer, err := utils.RecoverSigner(hash.Bytes(), raw.Signature)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("%w: %v", ErrInvalidSolverSignature, err)
+	}
+
+	if signer != raw.From {
+		return nil, common.Hash{}, fmt.Errorf("%w: signed by %s, expected %s", ErrInvalidSolverSignature, signer, raw.From)
+	}
+
+	return raw, hash, nil
+}
+
+// solverOperationHash returns the EIP-712 hash of the operation on the chain of the dApp
+func (d *DApp) solverOperationHash(op *types.SolverOperationRaw) (common.Hash, error) {
+	return hashSolverOperation(op.Decode(), d.cfg.ChainID)
+}
+
+// filterSolverOperations returns a copy of ops without duplicates, identified by their EIP-712 hash,
+// and without the operations whose deadline block has passed. Deadlines are only checked when the
+// latest block is tracked
+func (d *DApp) filterSolverOperations(ops []types.SolverOperationRaw) []types.SolverOperationRaw {
+	blockNumber, checkDeadline := d.blocks.BlockNumber()
+	latestBlock := new(big.Int).SetUint64(blockNumber)
+
+	seen := make(map[common.Hash]struct{}, len(ops))
+	result := make([]types.SolverOperationRaw, 0, len(ops))
+
+	for _, op := range ops {
+		hash, err := d.solverOperationHash(&op)
+		if err != nil {
+			logger.Warn("failed to compute solver operation hash", "error", err, "from", op.From)
+			continue
+		}
+
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+
+		// a zero deadline is treated as no deadline
+		if checkDeadline && op.Deadline != nil && op.Deadline.ToInt().Sign() > 0 && op.Deadline.ToInt().Cmp(latestBlock) < 0 {
+			continue
+		}
+
+		result = append(result, op)
+	}
+
+	return result
+}
+
+// SortSolverOperationsByBid sorts ops by bid amount, highest first. Operations with equal bids keep their arrival order
+func SortSolverOperationsByBid(ops []types.SolverOperationRaw) {
+	sort.SliceStable(ops, func(a, b int) bool {
+		return bidAmount(&ops[a]).Cmp(bidAmount(&ops[b])) > 0
+	})
+}
+
+func bidAmount(op *types.SolverOperationRaw) *big.Int {
+	if op.BidAmount == nil {
+		return new(big.Int)
+	}
+
+	return op.BidAmount.ToInt()
+}
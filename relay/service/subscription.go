@@ -1,61 +1,362 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
 	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
 	"github.com/cornelk/hashmap"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
-	"github.com/sourcegraph/jsonrpc2"
 
-	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/metrics"
 )
 
 const (
-	notificationChannelSize = 10000
-
-	SubscriptionTypeIntent SubscriptionType = "intent"
+	SubscriptionTypeIntent         SubscriptionType = "intent"
+	SubscriptionTypeIntentSolution SubscriptionType = "intentSolution"
 )
 
 var (
 	validSubscriptionTypes = map[SubscriptionType]struct{}{
-		SubscriptionTypeIntent: {},
+		SubscriptionTypeIntent:         {},
+		SubscriptionTypeIntentSolution: {},
 	}
 
 	validSubscriptionTypeList = []SubscriptionType{
 		SubscriptionTypeIntent,
+		SubscriptionTypeIntentSolution,
 	}
 )
 
+var (
+	// ErrConnectionNotFound is returned for the subscriptions of a connection that is not registered or already disconnected
+	ErrConnectionNotFound = errors.New("connection not found")
+	// ErrMinDeadlineUnsupported is returned for the min_deadline filter when no Atlas chain node is configured
+	ErrMinDeadlineUnsupported = errors.New("min_deadline filter requires the Atlas chain node of atlas.eth-rpc-url")
+)
+
+// ConnectionKind is the API a client connection holding subscriptions came through
+type ConnectionKind string
+
+const (
+	ConnectionKindSolverSocket ConnectionKind = "solver_socket"
+	ConnectionKindGRPCStream   ConnectionKind = "grpc_stream"
+	ConnectionKindDAppSocket   ConnectionKind = "dapp_socket"
+	// ConnectionKindSolverOperationsStream is a Server-Sent Events stream of the solver operations of an intent
+	ConnectionKindSolverOperationsStream ConnectionKind = "solver_operations_stream"
+)
+
+// ConnectionInfo describes a live client connection holding subscriptions
+type ConnectionInfo struct {
+	ID   string         `json:"id"`
+	Kind ConnectionKind `json:"kind"`
+	// RemoteAddress is the address of the client, the X-Forwarded-For address when the peer is a trusted proxy
+	RemoteAddress string `json:"remote_address"`
+	// Identity is the authenticated caller, empty when authentication is disabled
+	Identity string `json:"identity,omitempty"`
+	// Solver is the solver of a solver connection, DApp the dApp of a dApp connection
+	Solver        string    `json:"solver,omitempty"`
+	DApp          string    `json:"dapp,omitempty"`
+	ConnectedAt   time.Time `json:"connected_at"`
+	Subscriptions int       `json:"subscriptions"`
+}
+
 type Subscription struct {
-	ID                  string
+	ID string
+	// NotificationChannel buffers the notifications of the subscription, it is closed once the subscription ends
 	NotificationChannel chan interface{}
 	Type                SubscriptionType
-	conn                *jsonrpc2.Conn
+	Filter              SubscriptionFilter
+	// Replayed is the number of missed intents queued when the subscription resumed from a sequence number
+	Replayed int
+	// ReplayTruncated reports that some of the missed intents were no longer kept for the replay
+	ReplayTruncated bool
+	connectionID    string
+	stats           *subscriptionStats
 }
 
 type SubscriptionType string
 
+// SubscriptionFilter narrows the notifications delivered to a subscription, empty fields match everything
+type SubscriptionFilter struct {
+	// Solver is the solver whose intents are delivered to intent subscriptions,
+	// it is set by the relay from the solver the connection is bound to
+	Solver string
+
+	// SolverAddress is the address of the solver the connection is bound to, set by the relay.
+	// intentSolution subscriptions only receive the solver operations from that address
+	SolverAddress common.Address
+
+	// IntentID limits intentSolution notifications to the solutions of a single intent
+	IntentID string
+
+	// the fields below are matched against the user operation of intent notifications
+	DAppAddress   common.Address
+	SenderAddress common.Address
+	ChainID       uint64
+	To            common.Address
+	Control       common.Address
+	// MinDeadline skips intents with fewer blocks left before their deadline, as of the latest block of the
+	// Atlas chain. The intents of other chains are skipped
+	MinDeadline uint64
+}
+
+// chainHead is the Atlas chain when a notification is matched, number is zero when the latest block is unknown
+type chainHead struct {
+	chainID uint64
+	number  uint64
+}
+
+// IntentSolutionNotification is the notification delivered to intentSolution subscriptions
+type IntentSolutionNotification struct {
+	IntentID        string                    `json:"intent_id"`
+	SolutionID      string                    `json:"solution_id"`
+	SolverOperation *types.SolverOperationRaw `json:"solver_operation"`
+}
+
+// hasIntentFilter reports whether the filter needs the decoded user operation of intent notifications
+func (f SubscriptionFilter) hasIntentFilter() bool {
+	return f.DAppAddress != (common.Address{}) || f.SenderAddress != (common.Address{}) || f.ChainID != 0 ||
+		f.To != (common.Address{}) || f.Control != (common.Address{}) || f.MinDeadline != 0
+}
+
+// matches reports whether the notification passes the filter,
+// userOp returns the decoded user operation of an intent notification or nil if it cannot be decoded
+func (f SubscriptionFilter) matches(n interface{}, userOp func() *types.UserOperationPartialRaw, head chainHead) bool {
+	switch n := n.(type) {
+	case *IntentSolutionNotification:
+		// the solver operations of the other solvers carry their bids, they are never delivered
+		if f.SolverAddress == (common.Address{}) || n.SolverOperation == nil || n.SolverOperation.From != f.SolverAddress {
+			return false
+		}
+
+		return f.IntentID == "" || f.IntentID == n.IntentID
+	case *IntentNotification:
+		if !f.hasIntentFilter() {
+			return true
+		}
+
+		op := userOp()
+		if op == nil {
+			return false
+		}
+
+		return f.matchesUserOperation(op, head)
+	default:
+		return true
+	}
+}
+
+func (f SubscriptionFilter) matchesUserOperation(op *types.UserOperationPartialRaw, head chainHead) bool {
+	if f.DAppAddress != (common.Address{}) && f.DAppAddress != op.Dapp {
+		return false
+	}
+
+	if f.SenderAddress != (common.Address{}) && f.SenderAddress != op.From {
+		return false
+	}
+
+	if f.ChainID != 0 && (op.ChainId == nil || !op.ChainId.ToInt().IsUint64() || op.ChainId.ToInt().Uint64() != f.ChainID) {
+		return false
+	}
+
+	if f.To != (common.Address{}) && f.To != op.To {
+		return false
+	}
+
+	if f.Control != (common.Address{}) && f.Control != op.Control {
+		return false
+	}
+
+	if f.MinDeadline != 0 && !f.matchesDeadline(op, head) {
+		return false
+	}
+
+	return true
+}
+
+// matchesDeadline reports whether at least MinDeadline blocks are left before the deadline of the user operation
+func (f SubscriptionFilter) matchesDeadline(op *types.UserOperationPartialRaw, head chainHead) bool {
+	if head.number == 0 || op.ChainId == nil || !op.ChainId.ToInt().IsUint64() || op.ChainId.ToInt().Uint64() != head.chainID {
+		return false
+	}
+
+	if op.Deadline == nil || !op.Deadline.ToInt().IsUint64() {
+		return false
+	}
+
+	deadline := op.Deadline.ToInt().Uint64()
+
+	return deadline >= head.number && deadline-head.number >= f.MinDeadline
+}
+
 type SubscriptionManager struct {
+	cfg config.SubscriptionsConfig
+	// lock serializes the changes to the connections and their subscriptions, and the closing of the
+	// notification channels with the notifications sent to them
+	lock sync.RWMutex
+	// connections are the live client connections, by connection id
+	connections map[string]*clientConnection
+	// intentsSubscriptions are the subscriptions of the live connections, by connection id
 	intentsSubscriptions *hashmap.Map[string, []Subscription]
+	// replays numbers the intent notifications and keeps the recent ones for the subscriptions resuming
+	replays *replayBuffer
+	// blocks tracks the Atlas chain of chainID for the min_deadline filter, nil when no Atlas chain node is configured
+	blocks   *blockTracker
+	chainID  uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// clientConnection is a live client connection
+type clientConnection struct {
+	info ConnectionInfo
+	// close closes the connection of the client, it is disconnected once the client handler notices
+	close func()
+	// subscriptions counts the subscriptions of a dApp connection, they are held by its handler
+	subscriptions func() int
 }
 
-func NewSubscriptionManager() *SubscriptionManager {
-	return &SubscriptionManager{
+// NewSubscriptionManager returns a subscription manager buffering the notifications of each subscription
+// and applying the slow consumer policy of cfg
+func NewSubscriptionManager(cfg config.SubscriptionsConfig) *SubscriptionManager {
+	s := &SubscriptionManager{
+		cfg:                  cfg,
+		connections:          make(map[string]*clientConnection),
 		intentsSubscriptions: hashmap.New[string, []Subscription](),
+		replays:              newReplayBuffer(cfg.ReplayBufferSize),
+		stop:                 make(chan struct{}),
+	}
+
+	if cfg.StatusInterval > 0 || cfg.MaxLag > 0 {
+		go s.run()
+	}
+
+	return s
+}
+
+// trackBlocks sets the blocks of the Atlas chain the min_deadline filter is checked against
+func (s *SubscriptionManager) trackBlocks(blocks *blockTracker, chainID uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.blocks = blocks
+	s.chainID = chainID
+}
+
+// head returns the latest block of the Atlas chain, the caller holds the lock
+func (s *SubscriptionManager) head() chainHead {
+	number, _ := s.blocks.BlockNumber()
+
+	return chainHead{chainID: s.chainID, number: number}
+}
+
+// Connect registers a client connection and returns its id, the subscriptions of the connection are keyed by it.
+// closeConn closes the connection of the client when it is evicted or the relay shuts down
+func (s *SubscriptionManager) Connect(info ConnectionInfo, closeConn func()) string {
+	info.ID = uuid.New().String()
+	info.ConnectedAt = time.Now()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.connections[info.ID] = &clientConnection{
+		info:  info,
+		close: closeConn,
+	}
+
+	return info.ID
+}
+
+// ConnectDApp registers a dApp connection and returns its ID. Its solution subscriptions are held by its handler,
+// subscriptions returns their count. closeConn closes the connection of the client when the relay shuts down
+func (s *SubscriptionManager) ConnectDApp(info ConnectionInfo, subscriptions func() int, closeConn func()) string {
+	info.ID = uuid.New().String()
+	info.ConnectedAt = time.Now()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.connections[info.ID] = &clientConnection{
+		info:          info,
+		close:         closeConn,
+		subscriptions: subscriptions,
 	}
+
+	return info.ID
 }
 
-func (s *SubscriptionManager) Subscribe(remoteAddress string, subscriptionType SubscriptionType, conn *jsonrpc2.Conn) (*Subscription, error) {
+// Disconnect ends the subscriptions of the connection and removes it from the live connections
+func (s *SubscriptionManager) Disconnect(connectionID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	subs, _ := s.intentsSubscriptions.Get(connectionID)
+	for i := range subs {
+		close(subs[i].NotificationChannel)
+		metrics.ActiveSubscriptions.WithLabelValues(string(subs[i].Type)).Dec()
+	}
+
+	s.intentsSubscriptions.Del(connectionID)
+	delete(s.connections, connectionID)
+}
+
+// Connections returns the live connections, oldest first
+func (s *SubscriptionManager) Connections() []ConnectionInfo {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	connections := make([]ConnectionInfo, 0, len(s.connections))
+	for id, conn := range s.connections {
+		info := conn.info
+		if conn.subscriptions != nil {
+			info.Subscriptions = conn.subscriptions()
+		} else {
+			subs, _ := s.intentsSubscriptions.Get(id)
+			info.Subscriptions = len(subs)
+		}
+		connections = append(connections, info)
+	}
+
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].ConnectedAt.Before(connections[j].ConnectedAt)
+	})
+
+	return connections
+}
+
+// Subscribe adds a subscription to the connection. An intent subscription resuming from a sequence number,
+// fromSequence is not 0, first gets the kept intents from that sequence number on, then the live ones.
+// fromEpoch is the epoch of the sequence number, the intents of another run of the relay are not kept
+func (s *SubscriptionManager) Subscribe(connectionID string, subscriptionType SubscriptionType, filter SubscriptionFilter,
+	fromEpoch string, fromSequence uint64,
+) (*Subscription, error) {
 	_, valid := validSubscriptionTypes[subscriptionType]
 	if !valid {
 		return nil, fmt.Errorf("invalid 'subscription_type' param: '%s', valid values are: %v", subscriptionType, validSubscriptionTypeList)
 	}
 
-	subs, exists := s.intentsSubscriptions.Get(remoteAddress)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.connections[connectionID]; !ok {
+		return nil, ErrConnectionNotFound
+	}
+
+	if filter.MinDeadline != 0 && s.blocks == nil {
+		return nil, ErrMinDeadlineUnsupported
+	}
+
+	subs, exists := s.intentsSubscriptions.Get(connectionID)
 	if exists {
 		for i := range subs {
-			if subs[i].Type == subscriptionType {
+			if subs[i].Type == subscriptionType && subs[i].Filter == filter {
 				return nil, fmt.Errorf("subscription already exists for type: %s, id: %s", subscriptionType, subs[i].ID)
 			}
 		}
@@ -63,23 +364,41 @@ func (s *SubscriptionManager) Subscribe(remoteAddress string, subscriptionType S
 
 	sub := Subscription{
 		ID:                  uuid.New().String(),
-		NotificationChannel: make(chan interface{}, notificationChannelSize),
+		NotificationChannel: make(chan interface{}, s.cfg.Buffer()),
 		Type:                subscriptionType,
-		conn:                conn,
+		Filter:              filter,
+		connectionID:        connectionID,
+		stats:               &subscriptionStats{},
+	}
+
+	// no intent is delivered while the lock is held, so the replayed intents are followed by the live ones without a gap
+	if fromSequence != 0 {
+		err := s.replay(&sub, fromEpoch, fromSequence)
+		if err != nil {
+			return nil, err
+		}
 	}
+
 	subs = append(subs, sub)
-	s.intentsSubscriptions.Set(remoteAddress, subs)
+	s.intentsSubscriptions.Set(connectionID, subs)
+
+	metrics.ActiveSubscriptions.WithLabelValues(string(subscriptionType)).Inc()
 
 	return &sub, nil
 }
 
-func (s *SubscriptionManager) Unsubscribe(remoteAddress, subscriptionID string) error {
+// Unsubscribe ends a subscription of the connection
+func (s *SubscriptionManager) Unsubscribe(connectionID, subscriptionID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
 	exists := false
-	subs, _ := s.intentsSubscriptions.Get(remoteAddress)
+	subs, _ := s.intentsSubscriptions.Get(connectionID)
 
 	for i := range subs {
 		if subs[i].ID == subscriptionID {
 			close(subs[i].NotificationChannel)
+			metrics.ActiveSubscriptions.WithLabelValues(string(subs[i].Type)).Dec()
 			subs = append(subs[:i], subs[i+1:]...)
 			exists = true
 			break
@@ -90,41 +409,95 @@ func (s *SubscriptionManager) Unsubscribe(remoteAddress, subscriptionID string)
 		return fmt.Errorf("subscription not found for id: %s", subscriptionID)
 	}
 
-	s.intentsSubscriptions.Set(remoteAddress, subs)
+	s.intentsSubscriptions.Set(connectionID, subs)
 
 	return nil
 }
 
+// Notify delivers the notification to the matching subscriptions
 func (s *SubscriptionManager) Notify(n interface{}) {
-	var subType SubscriptionType
+	s.lock.RLock()
+	evicted := s.notify(n, "")
+	s.lock.RUnlock()
 
-	switch n.(type) {
-	case *sdk.OnIntentsNotification:
+	s.evictAll(evicted)
+}
+
+// NotifyIntent numbers the intent received by the solver, keeps it for the replays
+// and delivers it to the matching intent subscriptions of the solver
+func (s *SubscriptionManager) NotifyIntent(solver string, n *sdk.OnIntentsNotification) {
+	s.lock.RLock()
+	s.replays.lock.Lock()
+	evicted := s.notify(s.replays.add(solver, n), solver)
+	s.replays.lock.Unlock()
+	s.lock.RUnlock()
+
+	s.evictAll(evicted)
+}
+
+// notify delivers the notification to the matching subscriptions and returns the connections to evict,
+// the caller holds the read lock
+func (s *SubscriptionManager) notify(n interface{}, solver string) []string {
+	var (
+		subType      SubscriptionType
+		decodeUserOp func() *types.UserOperationPartialRaw
+	)
+
+	switch n := n.(type) {
+	case *IntentNotification:
 		subType = SubscriptionTypeIntent
+		// the user operation is decoded at most once and only if a subscription filters on it
+		decodeUserOp = intentUserOperation(n)
+	case *IntentSolutionNotification:
+		subType = SubscriptionTypeIntentSolution
 	default:
-		return
+		return nil
 	}
 
+	var evicted []string
+
+	head := s.head()
+
 	s.intentsSubscriptions.Range(func(key string, value []Subscription) bool {
-		for _, subscription := range value {
-			if subscription.Type == subType {
-				select {
-				case subscription.NotificationChannel <- n:
-				default:
-					logger.Warn("notification channel for subscription is full, dropping notification")
+		for i := range value {
+			subscription := &value[i]
+
+			if subType == SubscriptionTypeIntent && subscription.Filter.Solver != solver {
+				continue
+			}
+
+			if subscription.Type == subType && subscription.Filter.matches(n, decodeUserOp, head) {
+				if !s.deliver(subscription, n) {
+					evicted = append(evicted, subscription.connectionID)
 				}
 			}
 		}
 
 		return true
 	})
+
+	return evicted
 }
 
+// evictAll evicts the clients once the lock is released, their handlers disconnect them
+func (s *SubscriptionManager) evictAll(connectionIDs []string) {
+	for _, connectionID := range connectionIDs {
+		s.evict(connectionID, "notification buffer full")
+	}
+}
+
+// Close stops the status notifications and closes the connections of the clients
 func (s *SubscriptionManager) Close() {
-	s.intentsSubscriptions.Range(func(key string, value []Subscription) bool {
-		for _, subscription := range value {
-			_ = subscription.conn.Close()
-		}
-		return true
-	})
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	s.lock.RLock()
+	closers := make([]func(), 0, len(s.connections))
+	for _, conn := range s.connections {
+		closers = append(closers, conn.close)
+	}
+	s.lock.RUnlock()
+
+	for _, closeConn := range closers {
+		closeConn()
+	}
 }
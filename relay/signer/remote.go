@@ -0,0 +1,89 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// SignHashMethod is the JSON-RPC method of the remote signer protocol signing a 32 bytes hash as is.
+// Clef has no such method, its account_signData prefixes the data of every content type,
+// while the BDN requests are signed over the bare Keccak-256 hash of their payload
+const SignHashMethod = "relay_signHash"
+
+// remoteSigner signs with an account of a remote signer speaking the relay signer protocol: the account_signTypedData
+// method of the Clef JSON-RPC API for the EIP-712 typed data and SignHashMethod for the hashes of the BDN requests
+type remoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+func newRemoteSigner(ctx context.Context, url string, address common.Address) (*remoteSigner, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+
+	return &remoteSigner{
+		client:  client,
+		address: address,
+	}, nil
+}
+
+func (s *remoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *remoteSigner) SignHash(ctx context.Context, hash []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+
+	err := s.client.CallContext(ctx, &signature, SignHashMethod, common.NewMixedcaseAddress(s.address), hexutil.Bytes(hash))
+	if err != nil {
+		return nil, fmt.Errorf("remote signer failed to sign hash: %w", err)
+	}
+
+	return s.verify(hash, signature)
+}
+
+func (s *remoteSigner) SignTypedData(ctx context.Context, data *apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(*data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	var signature hexutil.Bytes
+
+	err = s.client.CallContext(ctx, &signature, "account_signTypedData", common.NewMixedcaseAddress(s.address), data)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer failed to sign typed data: %w", err)
+	}
+
+	return s.verify(hash, signature)
+}
+
+// verify normalizes V of the signature to 0 or 1, the remote signer returns 27 or 28 like Clef, and checks that the account signed the hash
+func (s *remoteSigner) verify(hash, signature []byte) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("remote signer returned a signature of %d bytes", len(signature))
+	}
+
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+
+	if crypto.PubkeyToAddress(*pubKey) != s.address {
+		return nil, fmt.Errorf("remote signer did not sign the hash with the key of %s", s.address)
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+)
+
+// Signer signs messages with the key of an Ethereum account.
+// The signatures are in the [R || S || V] format where V is 0 or 1, as returned by crypto.Sign
+type Signer interface {
+	// Address returns the address of the account
+	Address() common.Address
+	// SignHash signs the 32 bytes hash as is, without any prefix
+	SignHash(ctx context.Context, hash []byte) ([]byte, error)
+	// SignTypedData signs the EIP-712 hash of the typed data
+	SignTypedData(ctx context.Context, data *apitypes.TypedData) ([]byte, error)
+}
+
+// New returns the signer of the plaintext private key, or of the keystore or remote signer of the config
+func New(ctx context.Context, privateKey string, cfg *config.SignerConfig) (Signer, error) {
+	switch {
+	case cfg.Keystore != "":
+		return newKeystoreSigner(cfg.Keystore, cfg.PasswordFile)
+	case cfg.RemoteURL != "":
+		return newRemoteSigner(ctx, cfg.RemoteURL, common.HexToAddress(cfg.Account))
+	default:
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+
+		return newKeySigner(key), nil
+	}
+}
+
+// SignPayload signs the Keccak256 hash of the payload, as the BDN expects the intents, solutions
+// and subscriptions to be signed
+func SignPayload(ctx context.Context, s Signer, payload []byte) (hash, signature []byte, err error) {
+	hash = crypto.Keccak256(payload)
+
+	signature, err = s.SignHash(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hash, signature, nil
+}
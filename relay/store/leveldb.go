@@ -0,0 +1,194 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/logger"
+)
+
+const (
+	intentKeyPrefix = "intent/"
+	// pruneInterval is how often the intents past the retention period are deleted
+	pruneInterval = time.Minute
+)
+
+// levelDBStore keeps the intents as JSON in an embedded LevelDB database, they survive restarts
+type levelDBStore struct {
+	db        *leveldb.DB
+	retention time.Duration
+
+	// lock serializes the updates of an intent, which are read-modify-write
+	lock sync.Mutex
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newLevelDBStore(path string, retention time.Duration) (*levelDBStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open LevelDB store %s: %w", path, err)
+	}
+
+	s := &levelDBStore{
+		db:        db,
+		retention: retention,
+		done:      make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.pruneLoop()
+
+	return s, nil
+}
+
+func (s *levelDBStore) PutIntent(intent *Intent) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.put(intent)
+}
+
+func (s *levelDBStore) Intent(intentID string) (*Intent, error) {
+	intent, err := s.get(intentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the intent may not be pruned yet
+	if s.expired(intent, time.Now()) {
+		return nil, ErrNotFound
+	}
+
+	return intent, nil
+}
+
+func (s *levelDBStore) AddSolution(intentID string, solution *Solution) error {
+	return s.update(intentID, func(intent *Intent) {
+		intent.Solutions = append(intent.Solutions, *solution)
+	})
+}
+
+func (s *levelDBStore) SetStatus(intentID string, status IntentStatus) error {
+	return s.update(intentID, func(intent *Intent) {
+		intent.setStatus(status, time.Now())
+	})
+}
+
+func (s *levelDBStore) CloseAuction(intentID string, auction *Auction) error {
+	return s.update(intentID, func(intent *Intent) {
+		intent.Auction = auction
+	})
+}
+
+func (s *levelDBStore) update(intentID string, update func(intent *Intent)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	intent, err := s.Intent(intentID)
+	if err != nil {
+		return err
+	}
+
+	update(intent)
+	intent.UpdatedAt = time.Now()
+
+	return s.put(intent)
+}
+
+func (s *levelDBStore) Close() error {
+	close(s.done)
+	s.wg.Wait()
+
+	return s.db.Close()
+}
+
+func (s *levelDBStore) put(intent *Intent) error {
+	data, err := json.Marshal(intent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal intent: %w", err)
+	}
+
+	err = s.db.Put([]byte(intentKeyPrefix+intent.ID), data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to write intent: %w", err)
+	}
+
+	return nil
+}
+
+func (s *levelDBStore) get(intentID string) (*Intent, error) {
+	data, err := s.db.Get([]byte(intentKeyPrefix+intentID), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read intent: %w", err)
+	}
+
+	var intent Intent
+	err = json.Unmarshal(data, &intent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal intent: %w", err)
+	}
+
+	return &intent, nil
+}
+
+func (s *levelDBStore) expired(intent *Intent, now time.Time) bool {
+	return now.Sub(intent.SubmittedAt) > s.retention
+}
+
+func (s *levelDBStore) pruneLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			err := s.prune()
+			if err != nil {
+				logger.Error("failed to prune intents store", "error", err)
+			}
+		}
+	}
+}
+
+// prune deletes the intents past the retention period
+func (s *levelDBStore) prune() error {
+	now := time.Now()
+	batch := new(leveldb.Batch)
+
+	it := s.db.NewIterator(util.BytesPrefix([]byte(intentKeyPrefix)), nil)
+	for it.Next() {
+		var intent Intent
+		err := json.Unmarshal(it.Value(), &intent)
+		if err != nil || s.expired(&intent, now) {
+			batch.Delete(append([]byte(nil), it.Key()...))
+		}
+	}
+	it.Release()
+
+	err := it.Error()
+	if err != nil {
+		return err
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	logger.Debug("pruning intents store", "intents", batch.Len())
+
+	return s.db.Write(batch, nil)
+}
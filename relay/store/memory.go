@@ -0,0 +1,105 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// memoryStore keeps the intents in memory, they are lost on restart
+type memoryStore struct {
+	// lock serializes the updates of an intent, which are read-modify-write
+	lock    sync.Mutex
+	intents *ttlcache.Cache[string, *Intent]
+}
+
+func newMemoryStore(retention time.Duration) *memoryStore {
+	s := &memoryStore{
+		intents: ttlcache.New[string, *Intent](
+			ttlcache.WithTTL[string, *Intent](retention),
+			ttlcache.WithDisableTouchOnHit[string, *Intent](),
+		),
+	}
+
+	go s.intents.Start()
+
+	return s
+}
+
+func (s *memoryStore) PutIntent(intent *Intent) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.intents.Set(intent.ID, copyIntent(intent), ttlcache.DefaultTTL)
+
+	return nil
+}
+
+func (s *memoryStore) Intent(intentID string) (*Intent, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	item := s.intents.Get(intentID)
+	if item == nil {
+		return nil, ErrNotFound
+	}
+
+	return copyIntent(item.Value()), nil
+}
+
+func (s *memoryStore) AddSolution(intentID string, solution *Solution) error {
+	return s.update(intentID, func(intent *Intent) {
+		intent.Solutions = append(intent.Solutions, *solution)
+	})
+}
+
+func (s *memoryStore) SetStatus(intentID string, status IntentStatus) error {
+	return s.update(intentID, func(intent *Intent) {
+		intent.setStatus(status, time.Now())
+	})
+}
+
+func (s *memoryStore) CloseAuction(intentID string, auction *Auction) error {
+	return s.update(intentID, func(intent *Intent) {
+		intent.Auction = auction
+	})
+}
+
+func (s *memoryStore) update(intentID string, update func(intent *Intent)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	item := s.intents.Get(intentID)
+	if item == nil {
+		return ErrNotFound
+	}
+
+	// updates do not extend the retention of the intent
+	ttl := time.Until(item.ExpiresAt())
+	if ttl <= 0 {
+		return ErrNotFound
+	}
+
+	// the stored intent is replaced rather than modified, the copies returned by Intent stay unchanged
+	intent := copyIntent(item.Value())
+	update(intent)
+	intent.UpdatedAt = time.Now()
+
+	s.intents.Set(intentID, intent, ttl)
+
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	s.intents.Stop()
+	return nil
+}
+
+// copyIntent returns a copy of the intent not sharing its solutions slice
+func copyIntent(intent *Intent) *Intent {
+	c := *intent
+	c.Solutions = append([]Solution(nil), intent.Solutions...)
+
+	return &c
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+)
+
+// DefaultRetention is how long the intents are kept when the config does not set it
+const DefaultRetention = 24 * time.Hour
+
+var ErrNotFound = errors.New("intent not found")
+
+// IntentStatus is the stage of an intent submitted through the relay
+type IntentStatus string
+
+const (
+	// IntentStatusOpen is the status of an intent collecting solutions
+	IntentStatusOpen IntentStatus = "open"
+	// IntentStatusBundled is the status of an intent whose bundle was built by the dApp
+	IntentStatusBundled IntentStatus = "bundled"
+	// IntentStatusExpired is the status of an intent no longer tracked by the relay before it was bundled
+	IntentStatusExpired IntentStatus = "expired"
+)
+
+// Intent is an intent submitted through the relay with the solutions received for it
+type Intent struct {
+	ID            string                  `json:"id"`
+	DApp          string                  `json:"dapp"`
+	ChainID       uint64                  `json:"chain_id"`
+	UserOperation *types.UserOperationRaw `json:"user_operation"`
+	Status        IntentStatus            `json:"status"`
+	SubmittedAt   time.Time               `json:"submitted_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+	// BundledAt is when the first bundle of the intent was built, zero until then
+	BundledAt time.Time `json:"bundled_at"`
+	// ExpiredAt is when the live window of the intent ended, zero until then
+	ExpiredAt time.Time  `json:"expired_at"`
+	Solutions []Solution `json:"solutions"`
+	// Auction is the result of the auction of the intent, nil while it is open or when the dApp does not auction its intents
+	Auction *Auction `json:"auction,omitempty"`
+}
+
+// Auction is the frozen result of the auction of an intent
+type Auction struct {
+	ClosedAt time.Time `json:"closed_at"`
+	// SolverOperations are the solver operations of the result, ranked by bid from the highest
+	SolverOperations []types.SolverOperationRaw `json:"solver_operations"`
+}
+
+// setStatus records the status change of the intent, a bundled intent keeps its status once its live window ends
+func (i *Intent) setStatus(status IntentStatus, at time.Time) {
+	switch status {
+	case IntentStatusBundled:
+		if i.BundledAt.IsZero() {
+			i.BundledAt = at
+		}
+	case IntentStatusExpired:
+		if i.ExpiredAt.IsZero() {
+			i.ExpiredAt = at
+		}
+		if i.Status == IntentStatusBundled {
+			return
+		}
+	}
+
+	i.Status = status
+}
+
+// Solution is a solver operation received from the BDN for an intent
+type Solution struct {
+	ID              string                   `json:"id"`
+	SolverOperation types.SolverOperationRaw `json:"solver_operation"`
+	ReceivedAt      time.Time                `json:"received_at"`
+}
+
+// Store keeps the intents submitted through the relay and their solutions for the retention period
+type Store interface {
+	// PutIntent records a new intent
+	PutIntent(intent *Intent) error
+	// Intent returns the intent with its solutions, ErrNotFound when it is unknown or past the retention period
+	Intent(intentID string) (*Intent, error)
+	// AddSolution appends a solution to the intent, ErrNotFound when the intent is unknown
+	AddSolution(intentID string, solution *Solution) error
+	// SetStatus updates the status of the intent and records when it was bundled or expired, ErrNotFound when the intent is unknown
+	SetStatus(intentID string, status IntentStatus) error
+	// CloseAuction records the result of the auction of the intent, ErrNotFound when the intent is unknown
+	CloseAuction(intentID string, auction *Auction) error
+	// Close releases the resources of the store
+	Close() error
+}
+
+// New returns the store of the config
+func New(cfg *config.StoreConfig) (Store, error) {
+	retention := cfg.Retention
+	if retention == 0 {
+		retention = DefaultRetention
+	}
+
+	switch cfg.Type {
+	case "", config.StoreTypeMemory:
+		return newMemoryStore(retention), nil
+	case config.StoreTypeLevelDB:
+		return newLevelDBStore(cfg.Path, retention)
+	default:
+		return nil, fmt.Errorf("unsupported store type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,110 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestAuction expects the solver operations of an intent to be frozen when its auction closes,
+// ranked by bid without the bids under the minimum and capped at the maximum solver count
+func TestAuction(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Auction = config.AuctionConfig{
+			DurationMS: 1000,
+			MinBid:     "1500",
+			MaxSolvers: 2,
+		}
+	})
+
+	intents := r.subscribeToIntents(t)
+	intentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	events := r.streamEvents(t, intentID)
+
+	var intent struct {
+		Intent []byte `json:"intent"`
+	}
+
+	select {
+	case msg := <-intents:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	// the operations are signed before any is submitted, the Atlas SDK hashes are not safe to compute
+	// concurrently with the hashes of the relay
+	var solverOps []*types.SolverOperation
+	for _, bid := range []int64{1_000, 3_000, 2_000, 4_000} {
+		solverOps = append(solverOps, newSolverOperationWithBid(t, r.solverKey, &partialUserOp, bid))
+	}
+
+	for _, solverOp := range solverOps {
+		r.submitSolverOperation(t, intentID, solverOp)
+	}
+
+	if status := r.bundleOperations(t, intentID); status != http.StatusConflict {
+		t.Fatalf("expected status %d bundling an open auction, got %d", http.StatusConflict, status)
+	}
+
+	var result struct {
+		IntentID         string                     `json:"intent_id"`
+		ClosedAt         time.Time                  `json:"closed_at"`
+		SolverOperations []types.SolverOperationRaw `json:"solver_operations"`
+	}
+
+	deadline := time.After(timeout)
+	for result.IntentID == "" {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatal("solver operations stream ended before the auction closed")
+			}
+
+			if event.name == "auctionClosed" {
+				if err := json.Unmarshal([]byte(event.data), &result); err != nil {
+					t.Fatalf("failed to decode auction result: %v", err)
+				}
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for the auction to close")
+		}
+	}
+
+	assertBids := func(source string, ops []types.SolverOperationRaw) {
+		t.Helper()
+
+		if len(ops) != 2 || ops[0].BidAmount.ToInt().Int64() != 4_000 || ops[1].BidAmount.ToInt().Int64() != 3_000 {
+			t.Fatalf("unexpected solver operations of the %s: %+v", source, ops)
+		}
+	}
+
+	assertBids("auction closed event", result.SolverOperations)
+	assertBids("solverOperations route", r.solverOperations(t, intentID))
+
+	if status := r.bundleOperations(t, intentID); status != http.StatusOK {
+		t.Fatalf("unexpected status %d bundling a closed auction", status)
+	}
+
+	var lifecycle service.IntentLifecycle
+	r.getIntent(t, intentID, &lifecycle)
+
+	if lifecycle.AuctionClosedAt == nil || !lifecycle.AuctionClosedAt.Equal(result.ClosedAt) {
+		t.Fatalf("unexpected auction close time in the lifecycle %+v", lifecycle)
+	}
+}
@@ -0,0 +1,132 @@
+package e2e
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/websocket"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+const jwtSecret = "test-jwt-secret"
+
+func TestAuthentication(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Auth = config.AuthConfig{
+			APIKeys: []config.APIKeyConfig{
+				{Name: "dapp", Key: "dapp-key", Permissions: []config.Permission{config.PermissionDApp}},
+				{Name: "admin", Key: "admin-key", Permissions: []config.Permission{config.PermissionAdmin}},
+			},
+			JWTSecret: jwtSecret,
+		}
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		status int
+	}{
+		{"no credentials", "", "", http.StatusUnauthorized},
+		{"unknown API key", "X-API-Key", "unknown-key", http.StatusUnauthorized},
+		{"missing permission", "X-API-Key", "dapp-key", http.StatusForbidden},
+		{"API key", "X-API-Key", "admin-key", http.StatusOK},
+		{"bearer API key", "Authorization", "Bearer admin-key", http.StatusOK},
+		{"JWT", "Authorization", "Bearer " + newJWT(t, jwtSecret, config.PermissionAdmin), http.StatusOK},
+		{"JWT with another secret", "Authorization", "Bearer " + newJWT(t, "other-secret", config.PermissionAdmin), http.StatusUnauthorized},
+		{"JWT without expiry", "Authorization", "Bearer " + signJWT(t, jwtSecret, jwt.MapClaims{
+			"sub":         "jwt-client",
+			"permissions": []config.Permission{config.PermissionAdmin},
+		}), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, r.baseURL+"/metrics", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func newJWT(t *testing.T, secret string, permissions ...config.Permission) string {
+	t.Helper()
+
+	return signJWT(t, secret, jwt.MapClaims{
+		"sub":         "jwt-client",
+		"exp":         time.Now().Add(time.Minute).Unix(),
+		"permissions": permissions,
+	})
+}
+
+// signJWT returns the HS256 JWT of the claims
+func signJWT(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign JWT: %v", err)
+	}
+
+	return signed
+}
+
+// TestWebSocketOrigins expects the WebSocket APIs to only accept the browser origins of the relay host
+// and of the allowed origins, besides the clients sending no origin
+func TestWebSocketOrigins(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.AllowedOrigins = []string{"https://app.example.com"}
+	})
+
+	tests := []struct {
+		name   string
+		origin string
+		status int
+	}{
+		{"no origin", "", http.StatusSwitchingProtocols},
+		{"relay host", r.baseURL, http.StatusSwitchingProtocols},
+		{"allowed origin", "https://app.example.com", http.StatusSwitchingProtocols},
+		{"other origin", "https://evil.example.com", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, path := range []string{"/ws/solver", "/ws/dapp"} {
+				header := http.Header{}
+				if tt.origin != "" {
+					header.Set("Origin", tt.origin)
+				}
+
+				c, resp, err := websocket.DefaultDialer.Dial("ws"+r.baseURL[len("http"):]+path, header)
+				if err == nil {
+					_ = c.Close()
+				}
+
+				if resp == nil || resp.StatusCode != tt.status {
+					t.Fatalf("expected status %d on %s, got %v (%v)", tt.status, path, resp, err)
+				}
+			}
+		})
+	}
+}
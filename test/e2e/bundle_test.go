@@ -0,0 +1,147 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/core"
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestBundleOperations builds the bundle of a chosen subset of the solver operations of an intent and expects
+// them in the chosen order, under a dApp operation chaining and signing them with the dApp key
+func TestBundleOperations(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	intents := r.subscribeToIntents(t)
+	userOp := newUserOperation(r.dAppKey)
+	r.submitUserOperation(t, userOp)
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	// the solver operations are signed and hashed before the first one is submitted, the EIP-712 hashing
+	// of the Atlas SDK is not safe to run concurrently with the relay hashing the received ones
+	solverOps := make([]*types.SolverOperation, 0, 3)
+	hashes := make([]common.Hash, 0, 3)
+	for _, bid := range []int64{1_000, 2_000, 3_000} {
+		op := newSolverOperationWithBid(t, r.solverKey, partialUserOp, bid)
+
+		hash, err := op.Hash(chainID)
+		if err != nil {
+			t.Fatalf("failed to hash solver operation: %v", err)
+		}
+
+		solverOps = append(solverOps, op)
+		hashes = append(hashes, hash)
+	}
+
+	for _, op := range solverOps {
+		r.submitSolverOperation(t, intentID, op)
+	}
+
+	r.querySolverOperations(t, intentID, url.Values{
+		"wait_ms":       {fmt.Sprint(timeout.Milliseconds())},
+		"min_solutions": {fmt.Sprint(len(solverOps))},
+	})
+
+	var bundle types.BundleRaw
+	status := r.postBundle(t, map[string]interface{}{
+		"intent_id":               intentID,
+		"solver_operation_hashes": []common.Hash{hashes[2], hashes[0]},
+	}, &bundle)
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d bundling operations", status)
+	}
+
+	decoded := bundle.Decode()
+	chosen := types.SolverOperations{solverOps[2], solverOps[0]}
+
+	if len(decoded.SolverOperations) != len(chosen) {
+		t.Fatalf("expected %d solver operations in the bundle, got %d", len(chosen), len(decoded.SolverOperations))
+	}
+
+	for i := range chosen {
+		if decoded.SolverOperations[i].BidAmount.Cmp(chosen[i].BidAmount) != 0 {
+			t.Fatalf("expected the solver operation bidding %s at position %d, got %s", chosen[i].BidAmount, i,
+				decoded.SolverOperations[i].BidAmount)
+		}
+	}
+
+	callChainHash, err := core.CallChainHash(userOp, chosen)
+	if err != nil {
+		t.Fatalf("failed to compute call chain hash: %v", err)
+	}
+
+	if decoded.DAppOperation.CallChainHash != callChainHash {
+		t.Fatalf("expected call chain hash %s, got %s", callChainHash, decoded.DAppOperation.CallChainHash)
+	}
+
+	if decoded.DAppOperation.From != crypto.PubkeyToAddress(r.dAppKey.PublicKey) {
+		t.Fatalf("dApp operation is not from the dApp, got %s", decoded.DAppOperation.From)
+	}
+
+	if err = decoded.DAppOperation.ValidateSignature(chainID); err != nil {
+		t.Fatalf("dApp operation signature is invalid: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		body   map[string]interface{}
+		status int
+	}{
+		{"unknown intent", map[string]interface{}{"intent_id": "unknown-intent"}, http.StatusNotFound},
+		{"unknown solver operation", map[string]interface{}{
+			"intent_id":               intentID,
+			"solver_operation_hashes": []common.Hash{hashes[0], common.HexToHash("0x01")},
+		}, http.StatusBadRequest},
+		{"missing intent", map[string]interface{}{}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := r.postBundle(t, tt.body, nil); status != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, status)
+			}
+		})
+	}
+}
+
+// postBundle posts the bundle request and decodes the bundle into v when it is not nil, it returns the status code
+func (r *relay) postBundle(t *testing.T, request interface{}, v interface{}) int {
+	t.Helper()
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("failed to marshal bundle request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.baseURL+"/bundleOperations", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to bundle operations: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if v != nil && resp.StatusCode == http.StatusOK {
+		if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("failed to decode bundle: %v", err)
+		}
+	}
+
+	return resp.StatusCode
+}
@@ -0,0 +1,159 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+	ws "github.com/sourcegraph/jsonrpc2/websocket"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestConnectionRegistry connects two solver sockets through the same trusted proxy and expects them to hold
+// the same subscription apart, to be listed with their forwarded addresses and to be dropped once disconnected
+func TestConnectionRegistry(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.TrustedProxies = []string{"127.0.0.0/8"}
+	})
+
+	forwardedFor := []string{"203.0.113.1", "203.0.113.2"}
+
+	var conns []*jsonrpc2.Conn
+	for _, address := range forwardedFor {
+		header := http.Header{}
+		header.Set("X-Forwarded-For", address+", 127.0.0.1")
+
+		conn := jsonrpc2.NewConn(context.Background(), ws.NewObjectStream(r.dialSolverWithHeader(t, header)),
+			&notificationHandler{notifications: make(chan json.RawMessage, 10)})
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+
+		// the subscriptions used to be keyed by remote address, so the second one was rejected as a duplicate
+		r.subscribe(t, conn, map[string]interface{}{"subscription_type": "intent"})
+
+		conns = append(conns, conn)
+	}
+
+	connections := r.connections(t)
+	if len(connections) != len(forwardedFor) {
+		t.Fatalf("expected %d connections, got %+v", len(forwardedFor), connections)
+	}
+
+	for i, connection := range connections {
+		if connection.RemoteAddress != forwardedFor[i] || connection.Kind != service.ConnectionKindSolverSocket ||
+			connection.Solver != config.DefaultSolverName || connection.Subscriptions != 1 || connection.ID == "" {
+			t.Fatalf("unexpected connection %+v", connection)
+		}
+	}
+
+	_ = conns[0].Close()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		connections = r.connections(t)
+		if len(connections) == 1 && connections[0].RemoteAddress == forwardedFor[1] {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("the closed connection is still listed: %+v", connections)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestDAppConnectionRegistry expects the dApp sockets and the solver operations streams to be listed
+// with their dApp and subscription count, and to be dropped once disconnected
+func TestDAppConnectionRegistry(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	intentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	conn := r.dialDAppRPC(t, &notificationHandler{notifications: make(chan json.RawMessage, 10)})
+	r.subscribe(t, conn, map[string]interface{}{"subscription_type": "solutions", "intent_id": intentID})
+
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/solverOperations/stream?intent_id="+intentID, nil)
+	if err != nil {
+		t.Fatalf("failed to create stream request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to stream solver operations: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d streaming solver operations", resp.StatusCode)
+	}
+
+	connections := r.connections(t)
+	kinds := []service.ConnectionKind{service.ConnectionKindDAppSocket, service.ConnectionKindSolverOperationsStream}
+	if len(connections) != len(kinds) {
+		t.Fatalf("expected %d connections, got %+v", len(kinds), connections)
+	}
+
+	for i, connection := range connections {
+		if connection.Kind != kinds[i] || connection.DApp != config.DefaultDAppName || connection.Solver != "" ||
+			connection.Subscriptions != 1 || connection.ID == "" {
+			t.Fatalf("unexpected connection %+v", connection)
+		}
+	}
+
+	_ = conn.Close()
+	_ = resp.Body.Close()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		connections = r.connections(t)
+		if len(connections) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("the closed connections are still listed: %+v", connections)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// connections lists the live connections of the relay
+func (r *relay) connections(t *testing.T) []service.ConnectionInfo {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/admin/connections", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to list connections: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d listing connections", resp.StatusCode)
+	}
+
+	var connections []service.ConnectionInfo
+	if err = json.NewDecoder(resp.Body).Decode(&connections); err != nil {
+		t.Fatalf("failed to decode connections: %v", err)
+	}
+
+	return connections
+}
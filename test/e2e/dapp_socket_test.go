@@ -0,0 +1,133 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+	ws "github.com/sourcegraph/jsonrpc2/websocket"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestDAppSocket submits a user operation over the dApp socket, subscribes to the solutions of its intent
+// and expects the solver operation to be pushed and returned by getSolverOperations
+func TestDAppSocket(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	intents := r.subscribeToIntents(t)
+
+	h := &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+	conn := r.dialDAppRPC(t, h)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	userOp := newUserOperation(r.dAppKey)
+
+	var submitted struct {
+		IntentID string `json:"intent_id"`
+	}
+
+	err := conn.Call(ctx, "submitUserOperation", types.NewUserOperationWithHintsRaw(chainID, userOp, []common.Address{userOp.To}), &submitted)
+	if err != nil {
+		t.Fatalf("failed to submit user operation: %v", err)
+	}
+
+	intentID, partialUserOp := receiveIntent(t, intents)
+	if intentID != submitted.IntentID {
+		t.Fatalf("expected intent %s, got %s", submitted.IntentID, intentID)
+	}
+
+	subscriptionID := r.subscribe(t, conn, map[string]interface{}{"subscription_type": "solutions", "intent_id": intentID})
+
+	solverOp := newSolverOperation(t, r.solverKey, partialUserOp)
+	r.submitSolverOperation(t, intentID, solverOp)
+
+	var solution struct {
+		SubscriptionID  string                   `json:"subscription_id"`
+		IntentID        string                   `json:"intent_id"`
+		SolverOperation types.SolverOperationRaw `json:"solver_operation"`
+	}
+
+	select {
+	case msg := <-h.notifications:
+		if err = json.Unmarshal(msg, &solution); err != nil {
+			t.Fatalf("failed to decode solution notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the solution notification")
+	}
+
+	if solution.SubscriptionID != subscriptionID || solution.IntentID != intentID || solution.SolverOperation.From != solverOp.From {
+		t.Fatalf("unexpected solution notification %+v", solution)
+	}
+
+	var ops []types.SolverOperationRaw
+	if err = conn.Call(ctx, "getSolverOperations", map[string]string{"intent_id": intentID}, &ops); err != nil {
+		t.Fatalf("failed to get solver operations: %v", err)
+	}
+
+	if len(ops) != 1 || ops[0].From != solverOp.From {
+		t.Fatalf("unexpected solver operations %+v", ops)
+	}
+
+	var unsubscribed string
+	if err = conn.Call(ctx, "unsubscribe", map[string]string{"subscription_id": subscriptionID}, &unsubscribed); err != nil {
+		t.Fatalf("failed to unsubscribe: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		params interface{}
+		code   int64
+	}{
+		{"unsubscribe twice", "unsubscribe", map[string]string{"subscription_id": subscriptionID}, jsonrpc2.CodeInvalidRequest},
+		{"intent not tracked", "subscribe", map[string]string{"subscription_type": "solutions", "intent_id": "unknown-intent"}, jsonrpc2.CodeInvalidRequest},
+		{"solver subscription type", "subscribe", map[string]string{"subscription_type": "intent", "intent_id": intentID}, jsonrpc2.CodeInvalidParams},
+		{"missing user operation", "submitUserOperation", map[string]string{}, jsonrpc2.CodeInvalidParams},
+		{"solver method", "submitSolverOperation", map[string]string{"intent_id": intentID}, jsonrpc2.CodeMethodNotFound},
+		{"negative limit", "getSolverOperations", map[string]interface{}{"intent_id": intentID, "limit": -1}, jsonrpc2.CodeInvalidParams},
+		{"unsupported sort", "getSolverOperations", map[string]string{"intent_id": intentID, "sort": "gas"}, jsonrpc2.CodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := conn.Call(ctx, tt.method, tt.params, nil)
+
+			var rpcErr *jsonrpc2.Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+				t.Fatalf("expected error code %d, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+// dialDAppRPC opens a JSON-RPC connection to the dApp WS endpoint, h handles the notifications of the relay
+func (r *relay) dialDAppRPC(t *testing.T, h jsonrpc2.Handler) *jsonrpc2.Conn {
+	t.Helper()
+
+	c, _, err := websocket.DefaultDialer.Dial("ws"+r.baseURL[len("http"):]+"/ws/dapp", nil)
+	if err != nil {
+		t.Fatalf("failed to connect to the dApp endpoint: %v", err)
+	}
+
+	conn := jsonrpc2.NewConn(context.Background(), ws.NewObjectStream(c), h)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn
+}
@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+func TestMultipleDApps(t *testing.T) {
+	keys := map[string]string{
+		"first":  hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:],
+		"second": hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:],
+	}
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.DApps = []config.DAppConfig{
+			{Name: "first", PrivateKey: keys["first"], Address: common.HexToAddress("0x01").Hex()},
+			{Name: "second", PrivateKey: keys["second"], Address: common.HexToAddress("0x02").Hex()},
+		}
+	})
+
+	for name, key := range keys {
+		userOp := newUserOperation(r.dAppKey)
+		body, err := json.Marshal(types.NewUserOperationWithHintsRaw(chainID, userOp, []common.Address{userOp.To}))
+		if err != nil {
+			t.Fatalf("failed to marshal user operation: %v", err)
+		}
+
+		resp, err := http.Post(r.baseURL+"/dapp/"+name+"/userOperation", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to submit user operation: %v", err)
+		}
+
+		var result struct {
+			IntentID string `json:"intent_id"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to decode user operation response: %v", err)
+		}
+
+		privateKey, err := crypto.HexToECDSA(key)
+		if err != nil {
+			t.Fatalf("invalid key: %v", err)
+		}
+
+		found := false
+		for _, intent := range r.gateway.Intents() {
+			if intent.ID == result.IntentID {
+				found = true
+
+				if intent.SenderAddress != crypto.PubkeyToAddress(privateKey.PublicKey).Hex() {
+					t.Fatalf("intent of dApp %s was not sent with its key", name)
+				}
+			}
+		}
+
+		if !found {
+			t.Fatalf("intent of dApp %s was not submitted to the gateway", name)
+		}
+	}
+
+	resp, err := http.Get(r.baseURL + "/dapp/unknown/solverOperations?intent_id=1")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d for an unknown dApp, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+// TestDAppCredentials expects the dApp credentials to be limited to the dApps they are bound to
+func TestDAppCredentials(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.DApps = []config.DAppConfig{
+			{Name: "a", PrivateKey: hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:], Address: common.HexToAddress("0x01").Hex()},
+			{Name: "b", PrivateKey: hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:], Address: common.HexToAddress("0x02").Hex()},
+		}
+		cfg.Auth = config.AuthConfig{
+			APIKeys: []config.APIKeyConfig{
+				{Name: "dapp-a", Key: "dapp-a-key", Permissions: []config.Permission{config.PermissionDApp}, DApps: []string{"a"}},
+				{Name: "dapp-default", Key: "dapp-default-key", Permissions: []config.Permission{config.PermissionDApp}},
+			},
+			JWTSecret: jwtSecret,
+		}
+	})
+
+	// the allowed requests reach the dApp handler, which fails them for the unknown intent
+	tests := []struct {
+		name      string
+		token     string
+		method    string
+		path      string
+		forbidden bool
+	}{
+		{"bound dApp", "dapp-a-key", http.MethodGet, "/dapp/a/solverOperations?intent_id=1", false},
+		{"other dApp", "dapp-a-key", http.MethodGet, "/dapp/b/solverOperations?intent_id=1", true},
+		{"other dApp user operation", "dapp-a-key", http.MethodPost, "/dapp/b/userOperation", true},
+		{"other dApp bundle", "dapp-a-key", http.MethodPost, "/dapp/b/bundleOperations", true},
+		{"other dApp intent", "dapp-a-key", http.MethodGet, "/dapp/b/intent/1", true},
+		{"default dApp of a bound key", "dapp-a-key", http.MethodGet, "/solverOperations?intent_id=1", true},
+		{"unbound key", "dapp-default-key", http.MethodGet, "/solverOperations?intent_id=1", false},
+		{"unbound key on another dApp", "dapp-default-key", http.MethodGet, "/dapp/a/solverOperations?intent_id=1", true},
+		{"JWT dApps claim", newDAppJWT(t, "b"), http.MethodGet, "/dapp/b/solverOperations?intent_id=1", false},
+		{"JWT on another dApp", newDAppJWT(t, "b"), http.MethodGet, "/dapp/a/solverOperations?intent_id=1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, r.baseURL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if (resp.StatusCode == http.StatusForbidden) != tt.forbidden {
+				t.Fatalf("expected forbidden %v, got status %d", tt.forbidden, resp.StatusCode)
+			}
+		})
+	}
+}
+
+// newDAppJWT returns a JWT granting the dapp permission on the dApps
+func newDAppJWT(t *testing.T, dApps ...string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":         "jwt-dapp",
+		"exp":         time.Now().Add(time.Minute).Unix(),
+		"permissions": []config.Permission{config.PermissionDApp},
+		"dapps":       dApps,
+	})
+
+	signed, err := token.SignedString([]byte(jwtSecret))
+	if err != nil {
+		t.Fatalf("failed to sign JWT: %v", err)
+	}
+
+	return signed
+}
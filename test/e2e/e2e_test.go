@@ -0,0 +1,500 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/FastLane-Labs/atlas-sdk-go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+	ws "github.com/sourcegraph/jsonrpc2/websocket"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/server"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+const (
+	authHeader = "test-auth-header"
+	chainID    = 137
+	timeout    = 10 * time.Second
+)
+
+// relay is a relay started against the fake gateway
+type relay struct {
+	baseURL   string
+	gateway   *fakegateway.Gateway
+	solverKey *ecdsa.PrivateKey
+	dAppKey   *ecdsa.PrivateKey
+	// apiKey is sent with the requests of the helpers when set
+	apiKey string
+	// stop shuts the relay down, it is also called on cleanup
+	stop func()
+}
+
+func TestUserOperationRoundTripWS(t *testing.T) {
+	testUserOperationRoundTrip(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+}
+
+func TestUserOperationRoundTripGRPC(t *testing.T) {
+	testUserOperationRoundTrip(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.GRPCURL = gateway.GRPCURL()
+		cfg.BDN.GRPCInsecure = true
+	})
+}
+
+// testUserOperationRoundTrip submits a user operation as the dApp, solves the resulting intent as a solver
+// subscribed to the relay and expects the dApp to get the solver operation back from /solverOperations
+func testUserOperationRoundTrip(t *testing.T, withBDN func(*config.Config, *fakegateway.Gateway)) {
+	r := startRelay(t, withBDN)
+
+	intents := r.subscribeToIntents(t)
+
+	userOp := newUserOperation(r.dAppKey)
+	intentID := r.submitUserOperation(t, userOp)
+
+	var intent struct {
+		IntentID string `json:"intentID"`
+		Intent   []byte `json:"intent"`
+	}
+
+	select {
+	case msg := <-intents:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	if intent.IntentID != intentID {
+		t.Fatalf("expected intent %s, got %s", intentID, intent.IntentID)
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	solverOp := newSolverOperation(t, r.solverKey, &partialUserOp)
+	r.submitSolverOperation(t, intentID, solverOp)
+
+	ops := r.solverOperations(t, intentID)
+	if len(ops) != 1 {
+		t.Fatalf("expected 1 solver operation, got %d", len(ops))
+	}
+
+	if ops[0].From != solverOp.From || ops[0].UserOpHash != partialUserOp.UserOpHash {
+		t.Fatalf("unexpected solver operation %+v", ops[0])
+	}
+
+	if solutions := r.gateway.Solutions(intentID); len(solutions) != 1 {
+		t.Fatalf("expected the gateway to get 1 solution, got %d", len(solutions))
+	}
+}
+
+func startRelay(t *testing.T, withBDN func(*config.Config, *fakegateway.Gateway)) *relay {
+	t.Helper()
+
+	gateway, err := fakegateway.New(authHeader)
+	if err != nil {
+		t.Fatalf("failed to start fake gateway: %v", err)
+	}
+	t.Cleanup(gateway.Close)
+
+	dAppKey := newKey(t)
+	solverKey := newKey(t)
+	port := freePort(t)
+
+	cfg := &config.Config{
+		LogLevel:         "error",
+		HTTPPort:         port,
+		DAppPrivateKey:   hexutil.Encode(crypto.FromECDSA(dAppKey))[2:],
+		SolverPrivateKey: hexutil.Encode(crypto.FromECDSA(solverKey))[2:],
+		DAppAddress:      crypto.PubkeyToAddress(dAppKey.PublicKey).Hex(),
+		Atlas:            config.AtlasConfig{ChainID: chainID},
+		BDN:              config.BDNConfig{AuthHeader: authHeader},
+	}
+	withBDN(cfg, gateway)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s, err := server.NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	go func() {
+		_ = s.Start(ctx)
+	}()
+
+	stop := sync.OnceFunc(s.Shutdown)
+	t.Cleanup(stop)
+
+	r := &relay{
+		baseURL:   fmt.Sprintf("http://127.0.0.1:%d", port),
+		gateway:   gateway,
+		solverKey: solverKey,
+		dAppKey:   dAppKey,
+		stop:      stop,
+	}
+
+	r.waitUntilReady(t)
+
+	return r
+}
+
+func (r *relay) waitUntilReady(t *testing.T) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(r.baseURL + "/ping")
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatal("relay did not become ready")
+}
+
+// withAPIKey adds the API key of the relay to the request
+func (r *relay) withAPIKey(req *http.Request) *http.Request {
+	if r.apiKey != "" {
+		req.Header.Set("X-API-Key", r.apiKey)
+	}
+
+	return req
+}
+
+// dialSolver connects to the solver WS endpoint
+func (r *relay) dialSolver(t *testing.T) *websocket.Conn {
+	t.Helper()
+
+	return r.dialSolverWithHeader(t, http.Header{})
+}
+
+// dialSolverWithHeader connects to the solver WS endpoint with extra request headers
+func (r *relay) dialSolverWithHeader(t *testing.T, header http.Header) *websocket.Conn {
+	t.Helper()
+
+	if r.apiKey != "" {
+		header.Set("X-API-Key", r.apiKey)
+	}
+
+	c, _, err := websocket.DefaultDialer.Dial("ws"+r.baseURL[len("http"):]+"/ws/solver", header)
+	if err != nil {
+		t.Fatalf("failed to connect to the solver endpoint: %v", err)
+	}
+
+	return c
+}
+
+// subscribeToIntents subscribes to intents over the solver WS endpoint and returns the intent notifications
+func (r *relay) subscribeToIntents(t *testing.T) <-chan json.RawMessage {
+	t.Helper()
+
+	h := &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+
+	conn := r.dialSolverRPC(t, h)
+
+	r.subscribe(t, conn, map[string]interface{}{"subscription_type": "intent"})
+
+	return h.notifications
+}
+
+// receiveIntent waits for the next intent notification and returns the intent id with its partial user operation
+func receiveIntent(t *testing.T, intents <-chan json.RawMessage) (string, *types.UserOperationPartialRaw) {
+	t.Helper()
+
+	var intent struct {
+		IntentID string `json:"intentID"`
+		Intent   []byte `json:"intent"`
+	}
+
+	select {
+	case msg := <-intents:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	return intent.IntentID, &partialUserOp
+}
+
+// dialSolverRPC opens a JSON-RPC connection to the solver WS endpoint, h handles the notifications of the relay
+func (r *relay) dialSolverRPC(t *testing.T, h jsonrpc2.Handler) *jsonrpc2.Conn {
+	t.Helper()
+
+	conn := jsonrpc2.NewConn(context.Background(), ws.NewObjectStream(r.dialSolver(t)), h)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn
+}
+
+// subscribe calls the subscribe method of the solver WS endpoint and returns the subscription id
+func (r *relay) subscribe(t *testing.T, conn *jsonrpc2.Conn, params map[string]interface{}) string {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var result struct {
+		SubscriptionID string `json:"subscription_id"`
+	}
+
+	if err := conn.Call(ctx, "subscribe", params, &result); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	if result.SubscriptionID == "" {
+		t.Fatal("subscribe replied without a subscription id")
+	}
+
+	return result.SubscriptionID
+}
+
+// notificationHandler receives the subscription notifications of the relay
+type notificationHandler struct {
+	notifications chan json.RawMessage
+}
+
+func (h *notificationHandler) Handle(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Notif && req.Params != nil {
+		h.notifications <- *req.Params
+	}
+}
+
+func (r *relay) submitUserOperation(t *testing.T, userOp *types.UserOperation) string {
+	t.Helper()
+
+	return r.submitUserOperationWithHints(t, userOp, []common.Address{userOp.To})
+}
+
+// submitUserOperationWithHints submits the user operation with the hints, the intent carries the from address,
+// value and data of a user operation submitted without hints
+func (r *relay) submitUserOperationWithHints(t *testing.T, userOp *types.UserOperation, hints []common.Address) string {
+	t.Helper()
+
+	body, err := json.Marshal(types.NewUserOperationWithHintsRaw(chainID, userOp, hints))
+	if err != nil {
+		t.Fatalf("failed to marshal user operation: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.baseURL+"/userOperation", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to submit user operation: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d submitting user operation", resp.StatusCode)
+	}
+
+	var result struct {
+		IntentID string `json:"intent_id"`
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode user operation response: %v", err)
+	}
+
+	return result.IntentID
+}
+
+// submittedSolverOperation is the acknowledgement of the submitSolverOperation method
+type submittedSolverOperation struct {
+	SolutionID          string      `json:"solution_id"`
+	AcceptedAt          time.Time   `json:"accepted_at"`
+	SolverOperationHash common.Hash `json:"solver_operation_hash"`
+}
+
+func (r *relay) submitSolverOperation(t *testing.T, intentID string, solverOp *types.SolverOperation) *submittedSolverOperation {
+	t.Helper()
+
+	submitted, err := r.callSubmitSolverOperation(t, intentID, solverOp.EncodeToRaw())
+	if err != nil {
+		t.Fatalf("failed to submit solver operation: %v", err)
+	}
+
+	return submitted
+}
+
+// callSubmitSolverOperation calls the submitSolverOperation method over the solver WS endpoint,
+// the errors of the relay are returned as *jsonrpc2.Error
+func (r *relay) callSubmitSolverOperation(t *testing.T, intentID string, solution interface{}) (*submittedSolverOperation, error) {
+	t.Helper()
+
+	conn := r.dialSolverRPC(t, jsonrpc2.HandlerWithError(
+		func(context.Context, *jsonrpc2.Conn, *jsonrpc2.Request) (interface{}, error) { return nil, nil },
+	))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var submitted submittedSolverOperation
+
+	err := conn.Call(ctx, "submitSolverOperation", map[string]interface{}{
+		"intent_id":       intentID,
+		"intent_solution": solution,
+	}, &submitted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &submitted, nil
+}
+
+func (r *relay) solverOperations(t *testing.T, intentID string) []types.SolverOperationRaw {
+	t.Helper()
+
+	q := url.Values{}
+	q.Set("intent_id", intentID)
+	q.Set("wait_ms", fmt.Sprint(timeout.Milliseconds()))
+
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/solverOperations?"+q.Encode(), nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to get solver operations: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d getting solver operations", resp.StatusCode)
+	}
+
+	var ops []types.SolverOperationRaw
+	if err = json.NewDecoder(resp.Body).Decode(&ops); err != nil {
+		t.Fatalf("failed to decode solver operations: %v", err)
+	}
+
+	return ops
+}
+
+func newUserOperation(dAppKey *ecdsa.PrivateKey) *types.UserOperation {
+	return &types.UserOperation{
+		From:         common.HexToAddress("0x1000000000000000000000000000000000000001"),
+		To:           common.HexToAddress("0x2000000000000000000000000000000000000002"),
+		Value:        big.NewInt(0),
+		Gas:          big.NewInt(1_000_000),
+		MaxFeePerGas: big.NewInt(30_000_000_000),
+		Nonce:        big.NewInt(1),
+		Deadline:     big.NewInt(0),
+		Dapp:         crypto.PubkeyToAddress(dAppKey.PublicKey),
+		Control:      common.HexToAddress("0x3000000000000000000000000000000000000003"),
+		CallConfig:   0,
+		SessionKey:   common.Address{},
+		Data:         []byte{0x01, 0x02},
+		Signature:    []byte{},
+	}
+}
+
+func newSolverOperation(t *testing.T, solverKey *ecdsa.PrivateKey, userOp *types.UserOperationPartialRaw) *types.SolverOperation {
+	t.Helper()
+
+	return newSolverOperationWithBid(t, solverKey, userOp, 1_000)
+}
+
+func newSolverOperationWithBid(t *testing.T, solverKey *ecdsa.PrivateKey, userOp *types.UserOperationPartialRaw, bid int64) *types.SolverOperation {
+	t.Helper()
+
+	op := &types.SolverOperation{
+		From:         crypto.PubkeyToAddress(solverKey.PublicKey),
+		To:           common.HexToAddress("0x4000000000000000000000000000000000000004"),
+		Value:        big.NewInt(0),
+		Gas:          big.NewInt(500_000),
+		MaxFeePerGas: userOp.MaxFeePerGas.ToInt(),
+		Deadline:     big.NewInt(0),
+		Solver:       common.HexToAddress("0x5000000000000000000000000000000000000005"),
+		Control:      userOp.Control,
+		UserOpHash:   userOp.UserOpHash,
+		BidToken:     common.Address{},
+		BidAmount:    big.NewInt(bid),
+		Data:         []byte{0x03},
+	}
+
+	signSolverOperation(t, op, solverKey)
+
+	return op
+}
+
+// signSolverOperation signs the solver operation with the solver key, it is signed again after a change
+func signSolverOperation(t *testing.T, op *types.SolverOperation, solverKey *ecdsa.PrivateKey) {
+	t.Helper()
+
+	hash, err := op.Hash(chainID)
+	if err != nil {
+		t.Fatalf("failed to hash solver operation: %v", err)
+	}
+
+	op.Signature, err = utils.SignMessage(hash.Bytes(), solverKey)
+	if err != nil {
+		t.Fatalf("failed to sign solver operation: %v", err)
+	}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return key
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
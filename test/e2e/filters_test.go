@@ -0,0 +1,193 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakechain"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestIntentFilters expects each filter of the intent subscriptions to deliver only the intents
+// whose user operation matches it
+func TestIntentFilters(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	// every filter but chain_id matches the user operation changed by its own test case only
+	tests := []struct {
+		name    string
+		filters map[string]interface{}
+		userOp  func(userOp *types.UserOperation)
+		// withoutHints submits the user operation without hints, the from address is hidden by the hints
+		withoutHints bool
+		received     int
+	}{
+		{"dapp_address", map[string]interface{}{"dapp_address": "0xa000000000000000000000000000000000000001"}, func(userOp *types.UserOperation) {
+			userOp.Dapp = common.HexToAddress("0xa000000000000000000000000000000000000001")
+		}, false, 1},
+		{"sender_address", map[string]interface{}{"sender_address": "0xa000000000000000000000000000000000000002"}, func(userOp *types.UserOperation) {
+			userOp.From = common.HexToAddress("0xa000000000000000000000000000000000000002")
+		}, true, 1},
+		{"to", map[string]interface{}{"to": "0xa000000000000000000000000000000000000003"}, func(userOp *types.UserOperation) {
+			userOp.To = common.HexToAddress("0xa000000000000000000000000000000000000003")
+		}, false, 1},
+		{"control", map[string]interface{}{"control": "0xa000000000000000000000000000000000000004"}, func(userOp *types.UserOperation) {
+			userOp.Control = common.HexToAddress("0xa000000000000000000000000000000000000004")
+		}, false, 1},
+		// the dApp only submits user operations of its own chain, they all match its chain id and none another one
+		{"chain_id", map[string]interface{}{"chain_id": chainID}, nil, false, 5},
+		{"chain_id of another chain", map[string]interface{}{"chain_id": 80002}, nil, false, 0},
+	}
+
+	notifications := make([]chan json.RawMessage, len(tests))
+	for i, tt := range tests {
+		notifications[i] = make(chan json.RawMessage, 10)
+
+		r.subscribe(t, r.dialSolverRPC(t, &notificationHandler{notifications: notifications[i]}), map[string]interface{}{
+			"subscription_type": "intent",
+			"filters":           tt.filters,
+		})
+	}
+
+	intentIDs := make(map[int]string, len(tests))
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	for i, tt := range tests {
+		if tt.userOp == nil {
+			continue
+		}
+
+		userOp := newUserOperation(r.dAppKey)
+		tt.userOp(userOp)
+
+		if tt.withoutHints {
+			intentIDs[i] = r.submitUserOperationWithHints(t, userOp, nil)
+		} else {
+			intentIDs[i] = r.submitUserOperation(t, userOp)
+		}
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for received := 0; received < tt.received; received++ {
+				select {
+				case msg := <-notifications[i]:
+					var intent struct {
+						IntentID string `json:"intentID"`
+					}
+					if err := json.Unmarshal(msg, &intent); err != nil {
+						t.Fatalf("failed to decode intent notification: %v", err)
+					}
+
+					if tt.received == 1 && intent.IntentID != intentIDs[i] {
+						t.Fatalf("expected intent %s, got %s", intentIDs[i], intent.IntentID)
+					}
+				case <-time.After(timeout):
+					t.Fatalf("timed out waiting for intent notification %d", received+1)
+				}
+			}
+
+			select {
+			case msg := <-notifications[i]:
+				t.Fatalf("unexpected intent notification %s", msg)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+// TestMinDeadlineFilter expects the min_deadline filter to skip the intents with fewer blocks left before their
+// deadline than required, as of the latest block of the chain node
+func TestMinDeadlineFilter(t *testing.T) {
+	chain := newChain(t, 1_000)
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Atlas.EthRPCURL = chain.URL()
+	})
+
+	h := &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+
+	r.subscribe(t, r.dialSolverRPC(t, h), map[string]interface{}{
+		"subscription_type": "intent",
+		"filters":           map[string]interface{}{"min_deadline": 10},
+	})
+
+	// the deadline is an absolute block number, 5 and 50 blocks ahead of the chain
+	var intentIDs []string
+	for _, deadline := range []int64{1_005, 1_050} {
+		userOp := newUserOperation(r.dAppKey)
+		userOp.Deadline = big.NewInt(deadline)
+		intentIDs = append(intentIDs, r.submitUserOperation(t, userOp))
+	}
+
+	var intent struct {
+		IntentID string `json:"intentID"`
+	}
+
+	select {
+	case msg := <-h.notifications:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	if intent.IntentID != intentIDs[1] {
+		t.Fatalf("expected intent %s with 50 blocks left, got %s", intentIDs[1], intent.IntentID)
+	}
+
+	select {
+	case msg := <-h.notifications:
+		t.Fatalf("unexpected intent notification %s", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestMinDeadlineFilterWithoutChainNode expects the min_deadline filter to be rejected without a chain node
+func TestMinDeadlineFilterWithoutChainNode(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	conn := r.dialSolverRPC(t, &notificationHandler{notifications: make(chan json.RawMessage, 10)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := conn.Call(ctx, "subscribe", map[string]interface{}{
+		"subscription_type": "intent",
+		"filters":           map[string]interface{}{"min_deadline": 10},
+	}, nil)
+
+	var rpcErr *jsonrpc2.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc2.CodeInvalidRequest {
+		t.Fatalf("expected the subscription to be rejected, got %v", err)
+	}
+}
+
+// newChain starts a fake chain node at the block number
+func newChain(t *testing.T, blockNumber uint64) *fakechain.Chain {
+	t.Helper()
+
+	chain, err := fakechain.New(blockNumber)
+	if err != nil {
+		t.Fatalf("failed to start fake chain node: %v", err)
+	}
+	t.Cleanup(chain.Close)
+
+	return chain
+}
@@ -0,0 +1,216 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/server"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/server/pb"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestRelayGRPCAPI runs an intent round trip on the gRPC API of the relay: the solver subscribes to the intents
+// and submits its solver operation, the dApp submits the user operation and gets the solver operation
+func TestRelayGRPCAPI(t *testing.T) {
+	grpcPort := freePort(t)
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.GRPCPort = grpcPort
+		cfg.Auth = config.AuthConfig{
+			APIKeys: []config.APIKeyConfig{
+				{Name: "dapp", Key: "dapp-key", Permissions: []config.Permission{config.PermissionDApp}},
+				{Name: "solver", Key: "solver-key", Permissions: []config.Permission{config.PermissionSolver}},
+			},
+		}
+	})
+
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", grpcPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create gRPC client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	client := pb.NewRelayClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	dAppCtx := metadata.AppendToOutgoingContext(ctx, "x-api-key", "dapp-key")
+	solverCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer solver-key")
+
+	userOp := newUserOperation(r.dAppKey)
+	body, err := json.Marshal(types.NewUserOperationWithHintsRaw(chainID, userOp, []common.Address{userOp.To}))
+	if err != nil {
+		t.Fatalf("failed to marshal user operation: %v", err)
+	}
+
+	_, err = client.SubmitUserOperation(ctx, &pb.SubmitUserOperationRequest{UserOperation: body})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without credentials, got %v", err)
+	}
+
+	_, err = client.SubmitUserOperation(solverCtx, &pb.SubmitUserOperationRequest{UserOperation: body})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied with the solver key, got %v", err)
+	}
+
+	_, err = client.SubmitUserOperation(dAppCtx, &pb.SubmitUserOperationRequest{Dapp: "other", UserOperation: body})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for a dApp the key is not bound to, got %v", err)
+	}
+
+	intents, err := client.SubscribeIntents(solverCtx, &pb.SubscribeIntentsRequest{
+		Filter: &pb.IntentFilter{DappAddress: userOp.Dapp.Hex()},
+	})
+	if err != nil {
+		t.Fatalf("failed to subscribe to intents: %v", err)
+	}
+
+	// the subscription is registered by the time the relay sends the stream headers
+	if _, err = intents.Header(); err != nil {
+		t.Fatalf("failed to subscribe to intents: %v", err)
+	}
+
+	submitted, err := client.SubmitUserOperation(dAppCtx, &pb.SubmitUserOperationRequest{UserOperation: body})
+	if err != nil {
+		t.Fatalf("failed to submit user operation: %v", err)
+	}
+
+	intent, err := intents.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive intent: %v", err)
+	}
+
+	if intent.GetIntentId() != submitted.GetIntentId() {
+		t.Fatalf("received intent %s, expected %s", intent.GetIntentId(), submitted.GetIntentId())
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err = json.Unmarshal(intent.GetIntent(), &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	solverOp, err := json.Marshal(newSolverOperation(t, r.solverKey, &partialUserOp).EncodeToRaw())
+	if err != nil {
+		t.Fatalf("failed to marshal solver operation: %v", err)
+	}
+
+	reply, err := client.SubmitSolverOperation(solverCtx, &pb.SubmitSolverOperationRequest{
+		IntentId:        intent.GetIntentId(),
+		SolverOperation: solverOp,
+	})
+	if err != nil {
+		t.Fatalf("failed to submit solver operation: %v", err)
+	}
+
+	if common.HexToHash(reply.GetSolverOperationHash()) == (common.Hash{}) || reply.GetSolutionId() == "" || reply.GetAcceptedAt() == nil {
+		t.Fatalf("unexpected solver operation reply %+v", reply)
+	}
+
+	_, err = client.SubmitSolverOperation(solverCtx, &pb.SubmitSolverOperationRequest{
+		IntentId:        "unknown-intent",
+		SolverOperation: solverOp,
+	})
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("expected Aborted for an intent the BDN rejects, got %v", err)
+	}
+
+	solverOps, err := client.GetSolverOperations(dAppCtx, &pb.GetSolverOperationsRequest{
+		IntentId: submitted.GetIntentId(),
+		WaitMs:   uint32(timeout.Milliseconds()),
+	})
+	if err != nil {
+		t.Fatalf("failed to get solver operations: %v", err)
+	}
+
+	if len(solverOps.GetSolverOperations()) != 1 {
+		t.Fatalf("expected 1 solver operation, got %d", len(solverOps.GetSolverOperations()))
+	}
+
+	var op types.SolverOperationRaw
+	if err = json.Unmarshal(solverOps.GetSolverOperations()[0], &op); err != nil {
+		t.Fatalf("failed to decode solver operation: %v", err)
+	}
+
+	if op.UserOpHash != partialUserOp.UserOpHash {
+		t.Fatalf("unexpected solver operation %+v", op)
+	}
+}
+
+// TestStartWithHTTPPortInUse expects the relay to fail to start, without serving the gRPC API,
+// when its HTTP port is taken
+func TestStartWithHTTPPortInUse(t *testing.T) {
+	gateway, err := fakegateway.New(authHeader)
+	if err != nil {
+		t.Fatalf("failed to start fake gateway: %v", err)
+	}
+	t.Cleanup(gateway.Close)
+
+	taken, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = taken.Close()
+	})
+
+	dAppKey := newKey(t)
+	grpcPort := freePort(t)
+
+	cfg := &config.Config{
+		LogLevel:         "error",
+		HTTPPort:         taken.Addr().(*net.TCPAddr).Port,
+		GRPCPort:         grpcPort,
+		DAppPrivateKey:   hexutil.Encode(crypto.FromECDSA(dAppKey))[2:],
+		SolverPrivateKey: hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:],
+		DAppAddress:      crypto.PubkeyToAddress(dAppKey.PublicKey).Hex(),
+		Atlas:            config.AtlasConfig{ChainID: chainID},
+		BDN:              config.BDNConfig{AuthHeader: authHeader, WSURL: gateway.WSURL()},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s, err := server.NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	t.Cleanup(s.Shutdown)
+
+	started := make(chan error, 1)
+	go func() {
+		started <- s.Start(ctx)
+	}()
+
+	select {
+	case err = <-started:
+		if err == nil {
+			t.Fatal("expected the start to fail")
+		}
+	case <-time.After(timeout):
+		t.Fatal("the start did not fail with the HTTP port in use")
+	}
+
+	if conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", grpcPort)); err == nil {
+		_ = conn.Close()
+		t.Fatal("the gRPC API is served although the relay failed to start")
+	}
+}
@@ -0,0 +1,58 @@
+package e2e
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestIntentSolutionSubscription expects every solution to be pushed once to the intentSolution subscribers,
+// even when the BDN delivers it again
+func TestIntentSolutionSubscription(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	intents := r.subscribeToIntents(t)
+
+	h := &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+	r.subscribe(t, r.dialSolverRPC(t, h), map[string]interface{}{"subscription_type": "intentSolution"})
+
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	r.submitSolverOperation(t, intentID, newSolverOperation(t, r.solverKey, partialUserOp))
+
+	var solution struct {
+		IntentID        string                   `json:"intent_id"`
+		SolverOperation types.SolverOperationRaw `json:"solver_operation"`
+	}
+
+	select {
+	case msg := <-h.notifications:
+		if err := json.Unmarshal(msg, &solution); err != nil {
+			t.Fatalf("failed to decode solution notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the solution notification")
+	}
+
+	if solution.IntentID != intentID {
+		t.Fatalf("expected a solution of intent %s, got %s", intentID, solution.IntentID)
+	}
+
+	r.gateway.RedeliverSolutions(intentID)
+
+	select {
+	case msg := <-h.notifications:
+		t.Fatalf("the redelivered solution was pushed again: %s", msg)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
@@ -0,0 +1,176 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/store"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestIntentLifecycle follows an intent from its submission to its bundle through /intent/{id}
+func TestIntentLifecycle(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	if status := r.getIntent(t, "unknown-intent", nil); status != http.StatusNotFound {
+		t.Fatalf("expected status 404 for an unknown intent, got %d", status)
+	}
+
+	intents := r.subscribeToIntents(t)
+	intentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	var lifecycle service.IntentLifecycle
+	if status := r.getIntent(t, intentID, &lifecycle); status != http.StatusOK {
+		t.Fatalf("unexpected status %d getting the intent", status)
+	}
+
+	if lifecycle.Status != store.IntentStatusOpen || lifecycle.SubmittedAt.IsZero() || lifecycle.SolutionsReceived != 0 ||
+		lifecycle.FirstSolutionAt != nil || lifecycle.Expired || lifecycle.Bundled {
+		t.Fatalf("unexpected lifecycle of a submitted intent %+v", lifecycle)
+	}
+
+	var intent struct {
+		Intent []byte `json:"intent"`
+	}
+
+	select {
+	case msg := <-intents:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	r.submitSolverOperation(t, intentID, newSolverOperation(t, r.solverKey, &partialUserOp))
+
+	if ops := r.solverOperations(t, intentID); len(ops) != 1 {
+		t.Fatalf("expected 1 solver operation, got %d", len(ops))
+	}
+
+	r.getIntent(t, intentID, &lifecycle)
+	if lifecycle.SolutionsReceived != 1 || lifecycle.FirstSolutionAt == nil || lifecycle.LastSolutionAt == nil ||
+		lifecycle.FirstSolutionAt.Before(lifecycle.SubmittedAt) {
+		t.Fatalf("unexpected lifecycle of a solved intent %+v", lifecycle)
+	}
+
+	if status := r.bundleOperations(t, intentID); status != http.StatusOK {
+		t.Fatalf("unexpected status %d bundling operations", status)
+	}
+
+	r.getIntent(t, intentID, &lifecycle)
+	if lifecycle.Status != store.IntentStatusBundled || !lifecycle.Bundled || lifecycle.BundledAt == nil || lifecycle.Expired {
+		t.Fatalf("unexpected lifecycle of a bundled intent %+v", lifecycle)
+	}
+}
+
+// bundleOperations builds the bundle of the intent from all its solver operations and returns the status code
+func (r *relay) bundleOperations(t *testing.T, intentID string) int {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]string{"intent_id": intentID})
+	if err != nil {
+		t.Fatalf("failed to marshal bundle request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.baseURL+"/bundleOperations", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create bundle request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to bundle operations: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+// getIntent gets the lifecycle of the intent into v when the relay responds with 200 OK and returns the status code
+func (r *relay) getIntent(t *testing.T, intentID string, v interface{}) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/intent/"+intentID, nil)
+	if err != nil {
+		t.Fatalf("failed to create intent request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to get intent: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("failed to decode intent lifecycle: %v", err)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+// TestIntentExpiresAfterRestart expects an intent still open when the relay stopped to be reported expired
+// once its live window ended, although the restarted relay never tracked it
+func TestIntentExpiresAfterRestart(t *testing.T) {
+	storeCfg := config.StoreConfig{
+		Type: config.StoreTypeLevelDB,
+		Path: filepath.Join(t.TempDir(), "store"),
+	}
+
+	submittedAt := time.Now().Add(-2 * time.Minute)
+
+	// the intent is stored as the stopped relay left it
+	s, err := store.New(&storeCfg)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+
+	err = s.PutIntent(&store.Intent{
+		ID:          "open-intent",
+		DApp:        config.DefaultDAppName,
+		ChainID:     chainID,
+		Status:      store.IntentStatusOpen,
+		SubmittedAt: submittedAt,
+		UpdatedAt:   submittedAt,
+	})
+	if err != nil {
+		t.Fatalf("failed to store intent: %v", err)
+	}
+
+	if err = s.Close(); err != nil {
+		t.Fatalf("failed to close store: %v", err)
+	}
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Store = storeCfg
+	})
+
+	var lifecycle service.IntentLifecycle
+	if status := r.getIntent(t, "open-intent", &lifecycle); status != http.StatusOK {
+		t.Fatalf("unexpected status %d getting the intent", status)
+	}
+
+	if lifecycle.Status != store.IntentStatusExpired || !lifecycle.Expired || lifecycle.ExpiredAt == nil ||
+		!lifecycle.ExpiredAt.Equal(submittedAt.Add(time.Minute)) {
+		t.Fatalf("unexpected lifecycle of an intent open before the restart %+v", lifecycle)
+	}
+}
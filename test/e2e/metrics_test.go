@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"bufio"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestMetrics submits a user operation and a solver operation and expects their counters
+// and the latency of the route to be exported on /metrics
+func TestMetrics(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	// the metrics are global to the test binary, only their increase is checked
+	series := []string{
+		`bdn_operations_relay_user_operations_submitted_total{status="success"}`,
+		`bdn_operations_relay_solver_operations_submitted_total{status="success"}`,
+		`bdn_operations_relay_solver_operations_received_total`,
+		`bdn_operations_relay_http_request_duration_seconds_count{method="POST",route="SubmitUserOperation",status="200"}`,
+	}
+
+	before := r.scrapeMetrics(t)
+
+	intents := r.subscribeToIntents(t)
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	r.submitSolverOperation(t, intentID, newSolverOperation(t, r.solverKey, partialUserOp))
+	if ops := r.solverOperations(t, intentID); len(ops) != 1 {
+		t.Fatalf("expected 1 solver operation, got %d", len(ops))
+	}
+
+	// the latency of a request is recorded once its response is written
+	deadline := time.Now().Add(timeout)
+	for {
+		after := r.scrapeMetrics(t)
+
+		var missing []string
+		for _, s := range series {
+			if after[s] <= before[s] {
+				missing = append(missing, s)
+			}
+		}
+
+		if len(missing) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("the metrics did not increase: %v", missing)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// scrapeMetrics returns the value of every series exported on /metrics, keyed by its name and labels
+func (r *relay) scrapeMetrics(t *testing.T) map[string]float64 {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/metrics", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d scraping metrics", resp.StatusCode)
+	}
+
+	metrics := make(map[string]float64)
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.LastIndexByte(line, ' ')
+		if i < 0 {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			t.Fatalf("failed to parse metric %q: %v", line, err)
+		}
+
+		metrics[line[:i]] = value
+	}
+
+	if err = scanner.Err(); err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+
+	return metrics
+}
@@ -0,0 +1,103 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sourcegraph/jsonrpc2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/server/pb"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestRateLimit expects the route, the JSON-RPC method and the gRPC method of the same name to be limited apart
+func TestRateLimit(t *testing.T) {
+	grpcPort := freePort(t)
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.GRPCPort = grpcPort
+		cfg.RateLimit = config.RateLimitConfig{
+			// viper lower cases the map keys of the config file
+			Routes:  map[string]config.RateLimit{"submituseroperation": {Rate: 0.001, Burst: 1}},
+			Methods: map[string]config.RateLimit{"submituseroperation": {Rate: 0.001, Burst: 1}},
+			GRPC:    map[string]config.RateLimit{"submituseroperation": {Rate: 0.001, Burst: 1}},
+		}
+	})
+
+	userOp := newUserOperation(r.dAppKey)
+	body, err := json.Marshal(types.NewUserOperationWithHintsRaw(chainID, userOp, []common.Address{userOp.To}))
+	if err != nil {
+		t.Fatalf("failed to marshal user operation: %v", err)
+	}
+
+	for _, status := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		resp, err := http.Post(r.baseURL+"/userOperation", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to submit user operation: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != status {
+			t.Fatalf("expected status %d, got %d", status, resp.StatusCode)
+		}
+	}
+
+	conn := r.dialDAppRPC(t, &notificationHandler{notifications: make(chan json.RawMessage, 10)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// the route is limited, the method of the dApp socket still has its own token
+	userOp = newUserOperation(r.dAppKey)
+	params := types.NewUserOperationWithHintsRaw(chainID, userOp, []common.Address{userOp.To})
+
+	if err = conn.Call(ctx, "submitUserOperation", params, nil); err != nil {
+		t.Fatalf("failed to submit user operation over the dApp socket: %v", err)
+	}
+
+	var rpcErr *jsonrpc2.Error
+	if err = conn.Call(ctx, "submitUserOperation", params, nil); !errors.As(err, &rpcErr) || rpcErr.Code != -32003 {
+		t.Fatalf("expected error code -32003, got %v", err)
+	}
+
+	grpcConn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", grpcPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create gRPC client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = grpcConn.Close()
+	})
+
+	client := pb.NewRelayClient(grpcConn)
+
+	// the gRPC method has its own token as well
+	userOp = newUserOperation(r.dAppKey)
+	body, err = json.Marshal(types.NewUserOperationWithHintsRaw(chainID, userOp, []common.Address{userOp.To}))
+	if err != nil {
+		t.Fatalf("failed to marshal user operation: %v", err)
+	}
+
+	for _, code := range []codes.Code{codes.OK, codes.ResourceExhausted} {
+		_, err = client.SubmitUserOperation(ctx, &pb.SubmitUserOperationRequest{UserOperation: body})
+		if status.Code(err) != code {
+			t.Fatalf("expected gRPC code %s, got %v", code, err)
+		}
+	}
+
+	if len(r.gateway.Intents()) != 3 {
+		t.Fatalf("expected 3 intents submitted to the gateway, got %d", len(r.gateway.Intents()))
+	}
+}
@@ -0,0 +1,87 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestReconnect drops the connections of the gateway during an outage and expects /ping to report the relay
+// unavailable until it reconnected, then the intents and the solutions to flow again on the restored subscriptions
+func TestReconnect(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	// the subscription of the solver socket outlives the connection of the relay to the gateway
+	intents := r.subscribeToIntents(t)
+
+	r.gateway.Pause()
+	r.gateway.DropConnections()
+
+	r.waitForPingStatus(t, http.StatusServiceUnavailable)
+
+	r.gateway.Resume()
+
+	r.waitForPingStatus(t, http.StatusOK)
+
+	intentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	var intent struct {
+		IntentID string `json:"intentID"`
+		Intent   []byte `json:"intent"`
+	}
+
+	select {
+	case msg := <-intents:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification after reconnecting")
+	}
+
+	if intent.IntentID != intentID {
+		t.Fatalf("expected intent %s, got %s", intentID, intent.IntentID)
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	solverOp := newSolverOperation(t, r.solverKey, &partialUserOp)
+	r.submitSolverOperation(t, intentID, solverOp)
+
+	// the solution reaches the dApp through the solutions subscription restored on the new connection
+	ops := r.solverOperations(t, intentID)
+	if len(ops) != 1 || ops[0].From != solverOp.From {
+		t.Fatalf("unexpected solver operations after reconnecting %+v", ops)
+	}
+}
+
+// waitForPingStatus polls /ping until it responds with the status
+func (r *relay) waitForPingStatus(t *testing.T, status int) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(r.baseURL + "/ping")
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode == status {
+				return
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatalf("/ping did not respond with status %d", status)
+}
@@ -0,0 +1,235 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// sequencedIntent is the part of an intent notification the replay tests check
+type sequencedIntent struct {
+	IntentID string `json:"intentID"`
+	Sequence uint64 `json:"sequence"`
+	Epoch    string `json:"epoch"`
+}
+
+// TestSubscriptionResume reconnects a solver socket and expects the subscription resuming from the sequence
+// number after the last intent received to get the missed intents, then the live ones
+func TestSubscriptionResume(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Subscriptions.ReplayBufferSize = 10
+	})
+
+	// the observer stays connected to tell when the relay received the missed intents
+	observer := &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+	r.subscribe(t, r.dialSolverRPC(t, observer), map[string]interface{}{"subscription_type": "intent"})
+
+	h := &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+
+	conn := r.dialSolverRPC(t, h)
+	r.subscribe(t, conn, map[string]interface{}{"subscription_type": "intent"})
+
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	last := receiveSequencedIntent(t, h)
+	receiveSequencedIntent(t, observer)
+
+	_ = conn.Close()
+
+	missed := []string{
+		r.submitUserOperation(t, newUserOperation(r.dAppKey)),
+		r.submitUserOperation(t, newUserOperation(r.dAppKey)),
+	}
+
+	for range missed {
+		receiveSequencedIntent(t, observer)
+	}
+
+	h = &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+
+	conn = r.dialSolverRPC(t, h)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var resumed struct {
+		SubscriptionID  string `json:"subscription_id"`
+		Replayed        int    `json:"replayed"`
+		ReplayTruncated bool   `json:"replay_truncated"`
+	}
+
+	err := conn.Call(ctx, "subscribe", map[string]interface{}{
+		"subscription_type": "intent",
+		"from_epoch":        last.Epoch,
+		"from_sequence":     last.Sequence + 1,
+	}, &resumed)
+	if err != nil {
+		t.Fatalf("failed to resume the subscription: %v", err)
+	}
+
+	if resumed.SubscriptionID == "" || resumed.Replayed != len(missed) || resumed.ReplayTruncated {
+		t.Fatalf("unexpected resumed subscription %+v", resumed)
+	}
+
+	for i, intentID := range missed {
+		intent := receiveSequencedIntent(t, h)
+		if intent.IntentID != intentID || intent.Sequence != last.Sequence+uint64(i)+1 {
+			t.Fatalf("expected missed intent %s with sequence %d, got %+v", intentID, last.Sequence+uint64(i)+1, intent)
+		}
+	}
+
+	live := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	intent := receiveSequencedIntent(t, h)
+	if intent.IntentID != live || intent.Sequence != last.Sequence+uint64(len(missed))+1 {
+		t.Fatalf("expected live intent %s after the missed ones, got %+v", live, intent)
+	}
+}
+
+// receiveSequencedIntent waits for the next intent notification of the handler
+func receiveSequencedIntent(t *testing.T, h *notificationHandler) sequencedIntent {
+	t.Helper()
+
+	var intent sequencedIntent
+
+	select {
+	case msg := <-h.notifications:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	if intent.Sequence == 0 || intent.Epoch == "" {
+		t.Fatalf("intent notification %s has no sequence number or epoch", intent.IntentID)
+	}
+
+	return intent
+}
+
+// TestSubscriptionReplayBuffer expects the resuming subscriptions to get the intents still kept in the replay buffer
+func TestSubscriptionReplayBuffer(t *testing.T) {
+	tests := []struct {
+		name         string
+		replaySize   int
+		fromEpoch    string
+		fromSequence uint64
+		replayed     []uint64
+		truncated    bool
+		err          error
+	}{
+		{"kept intents", 3, "", 4, []uint64{4, 5}, false, nil},
+		{"caught up", 3, "", 6, nil, false, nil},
+		{"dropped intents", 3, "", 1, []uint64{4, 5}, true, nil},
+		{"relay restarted", 3, "", 100, []uint64{4, 5}, true, nil},
+		// the sequence numbers of another run designate other intents
+		{"other epoch", 3, "previous-run", 4, []uint64{4, 5}, true, nil},
+		{"replay disabled", 0, "", 1, nil, false, service.ErrReplayDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := service.NewSubscriptionManager(config.SubscriptionsConfig{ReplayBufferSize: tt.replaySize})
+			t.Cleanup(manager.Close)
+
+			for i := 1; i <= 5; i++ {
+				manager.NotifyIntent(config.DefaultSolverName, &sdk.OnIntentsNotification{IntentID: fmt.Sprint(i)})
+			}
+			// the intents of other solvers are not replayed
+			manager.NotifyIntent("other-solver", &sdk.OnIntentsNotification{IntentID: "other"})
+
+			connectionID := manager.Connect(service.ConnectionInfo{
+				Kind:   service.ConnectionKindSolverSocket,
+				Solver: config.DefaultSolverName,
+			}, func() {})
+
+			subscription, err := manager.Subscribe(connectionID, service.SubscriptionTypeIntent,
+				service.SubscriptionFilter{Solver: config.DefaultSolverName}, tt.fromEpoch, tt.fromSequence)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if subscription.Replayed != len(tt.replayed) || subscription.ReplayTruncated != tt.truncated {
+				t.Fatalf("expected %d replayed intents, truncated %v, got %d, %v", len(tt.replayed), tt.truncated,
+					subscription.Replayed, subscription.ReplayTruncated)
+			}
+
+			for _, sequence := range tt.replayed {
+				msg := <-subscription.NotificationChannel
+				if intent, ok := msg.(*service.IntentNotification); !ok || intent.Sequence != sequence ||
+					intent.IntentID != fmt.Sprint(sequence) {
+					t.Fatalf("expected intent with sequence %d to be replayed, got %+v", sequence, msg)
+				}
+			}
+
+			// the next intent is delivered live after the replayed ones
+			manager.NotifyIntent(config.DefaultSolverName, &sdk.OnIntentsNotification{IntentID: "live"})
+
+			msg := <-subscription.NotificationChannel
+			if intent, ok := msg.(*service.IntentNotification); !ok || intent.IntentID != "live" || intent.Sequence != 7 {
+				t.Fatalf("expected the live intent with sequence 7, got %+v", msg)
+			}
+		})
+	}
+}
+
+// TestSubscriptionReplayOverflow expects a replay which does not fit in the subscription buffer to fail,
+// whatever the slow consumer policy, rather than to drop some of the missed intents
+func TestSubscriptionReplayOverflow(t *testing.T) {
+	for _, policy := range []config.SlowConsumerPolicy{config.SlowConsumerPolicyDropNewest, config.SlowConsumerPolicyDropOldest} {
+		t.Run(string(policy), func(t *testing.T) {
+			manager := service.NewSubscriptionManager(config.SubscriptionsConfig{
+				BufferSize:         2,
+				SlowConsumerPolicy: policy,
+				ReplayBufferSize:   10,
+			})
+			t.Cleanup(manager.Close)
+
+			for i := 1; i <= 3; i++ {
+				manager.NotifyIntent(config.DefaultSolverName, &sdk.OnIntentsNotification{IntentID: fmt.Sprint(i)})
+			}
+
+			connectionID := manager.Connect(service.ConnectionInfo{
+				Kind:   service.ConnectionKindSolverSocket,
+				Solver: config.DefaultSolverName,
+			}, func() {})
+
+			filter := service.SubscriptionFilter{Solver: config.DefaultSolverName}
+
+			_, err := manager.Subscribe(connectionID, service.SubscriptionTypeIntent, filter, "", 1)
+			if !errors.Is(err, service.ErrReplayOverflow) {
+				t.Fatalf("expected error %v, got %v", service.ErrReplayOverflow, err)
+			}
+
+			subscription, err := manager.Subscribe(connectionID, service.SubscriptionTypeIntent, filter, "", 2)
+			if err != nil {
+				t.Fatalf("failed to resume the subscription: %v", err)
+			}
+
+			if subscription.Replayed != 2 || subscription.Dropped() != 0 {
+				t.Fatalf("expected 2 replayed intents without drop, got %d replayed, %d dropped",
+					subscription.Replayed, subscription.Dropped())
+			}
+		})
+	}
+}
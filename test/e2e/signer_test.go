@@ -0,0 +1,240 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/google/uuid"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/signer"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestSigners runs the dApp key in a remote signer and the solver key in an encrypted keystore
+func TestSigners(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+
+		cfg.DAppSigner = config.SignerConfig{
+			RemoteURL: startRemoteSigner(t, hexToKey(t, cfg.DAppPrivateKey), true),
+			Account:   cfg.DAppAddress,
+		}
+		cfg.DAppPrivateKey = ""
+
+		cfg.SolverSigner.Keystore, cfg.SolverSigner.PasswordFile = writeKeystore(t, hexToKey(t, cfg.SolverPrivateKey))
+		cfg.SolverPrivateKey = ""
+	})
+
+	intents := r.subscribeToIntents(t)
+	intentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	var intent struct {
+		Intent []byte `json:"intent"`
+	}
+
+	select {
+	case msg := <-intents:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	r.submitSolverOperation(t, intentID, newSolverOperation(t, r.solverKey, &partialUserOp))
+
+	if ops := r.solverOperations(t, intentID); len(ops) != 1 {
+		t.Fatalf("expected 1 solver operation, got %d", len(ops))
+	}
+
+	solutions := r.gateway.Solutions(intentID)
+	if len(solutions) != 1 || solutions[0].SolverAddress != crypto.PubkeyToAddress(r.solverKey.PublicKey).Hex() {
+		t.Fatal("solution was not submitted with the keystore key")
+	}
+
+	body, err := json.Marshal(map[string]string{"intent_id": intentID})
+	if err != nil {
+		t.Fatalf("failed to marshal bundle request: %v", err)
+	}
+
+	resp, err := http.Post(r.baseURL+"/bundleOperations", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to bundle operations: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d bundling operations", resp.StatusCode)
+	}
+
+	var bundle types.BundleRaw
+	if err = json.NewDecoder(resp.Body).Decode(&bundle); err != nil {
+		t.Fatalf("failed to decode bundle: %v", err)
+	}
+
+	if err = bundle.DAppOperation.Decode().ValidateSignature(chainID); err != nil {
+		t.Fatalf("dApp operation signed by the remote signer is invalid: %v", err)
+	}
+}
+
+// TestRemoteSignerWithoutSignHash expects a stock Clef to be refused for the BDN requests,
+// the signatures of its account_signData do not recover to the account over the bare hash
+func TestRemoteSignerWithoutSignHash(t *testing.T) {
+	key := hexToKey(t, hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:])
+
+	s, err := signer.New(context.Background(), "", &config.SignerConfig{
+		RemoteURL: startRemoteSigner(t, key, false),
+		Account:   key.Address.Hex(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create remote signer: %v", err)
+	}
+
+	if _, _, err = signer.SignPayload(context.Background(), s, []byte("payload")); err == nil {
+		t.Fatal("expected a signer without relay_signHash to fail signing the BDN payload")
+	}
+}
+
+// clef serves the account_signData and account_signTypedData methods of Clef with a single key
+type clef struct {
+	key *keystore.Key
+}
+
+// hashSigner serves the relay_signHash method the relay signer protocol adds to Clef
+type hashSigner struct {
+	clef *clef
+}
+
+// startRemoteSigner starts a signer of the relay signer protocol, the relay_signHash method is left out
+// to stand in for a stock Clef when withSignHash is false
+func startRemoteSigner(t *testing.T, key *keystore.Key, withSignHash bool) string {
+	t.Helper()
+
+	c := &clef{key: key}
+
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("account", c); err != nil {
+		t.Fatalf("failed to register remote signer: %v", err)
+	}
+
+	if withSignHash {
+		if err := srv.RegisterName("relay", &hashSigner{clef: c}); err != nil {
+			t.Fatalf("failed to register remote signer: %v", err)
+		}
+	}
+
+	httpSrv := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		httpSrv.Close()
+		srv.Stop()
+	})
+
+	return httpSrv.URL
+}
+
+// SignData signs the data as Clef does: the content types other than the structured ones
+// are signed as text, with the EIP-191 personal message prefix
+func (c *clef) SignData(contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	switch contentType {
+	case apitypes.DataTyped.Mime, apitypes.ApplicationClique.Mime, apitypes.IntendedValidator.Mime:
+		return nil, fmt.Errorf("content type %s is not supported by the fake signer", contentType)
+	default:
+		return c.sign(addr, accounts.TextHash(data))
+	}
+}
+
+func (c *clef) SignTypedData(addr common.MixedcaseAddress, data apitypes.TypedData) (hexutil.Bytes, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sign(addr, hash)
+}
+
+func (s *hashSigner) SignHash(addr common.MixedcaseAddress, hash hexutil.Bytes) (hexutil.Bytes, error) {
+	if len(hash) != common.HashLength {
+		return nil, fmt.Errorf("hash must be %d bytes", common.HashLength)
+	}
+
+	return s.clef.sign(addr, hash)
+}
+
+func (c *clef) sign(addr common.MixedcaseAddress, hash []byte) (hexutil.Bytes, error) {
+	if addr.Address() != c.key.Address {
+		return nil, fmt.Errorf("unknown account %s", addr.Address())
+	}
+
+	signature, err := crypto.Sign(hash, c.key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clef returns V as 27 or 28
+	signature[crypto.RecoveryIDOffset] += 27
+
+	return signature, nil
+}
+
+// writeKeystore encrypts the key in a keystore file and returns the paths of the keystore and password files
+func writeKeystore(t *testing.T, key *keystore.Key) (string, string) {
+	t.Helper()
+
+	const password = "test-password"
+
+	keyJSON, err := keystore.EncryptKey(key, password, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("failed to encrypt key: %v", err)
+	}
+
+	dir := t.TempDir()
+	keystoreFile := filepath.Join(dir, "keystore.json")
+	passwordFile := filepath.Join(dir, "password")
+
+	if err = os.WriteFile(keystoreFile, keyJSON, 0o600); err != nil {
+		t.Fatalf("failed to write keystore: %v", err)
+	}
+
+	if err = os.WriteFile(passwordFile, []byte(password+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write password file: %v", err)
+	}
+
+	return keystoreFile, passwordFile
+}
+
+func hexToKey(t *testing.T, privateKey string) *keystore.Key {
+	t.Helper()
+
+	key, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		t.Fatalf("invalid key: %v", err)
+	}
+
+	return &keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}
+}
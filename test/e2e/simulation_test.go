@@ -0,0 +1,130 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakechain"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// simulatedSolverOperation is a solver operation returned by /solverOperations with its simulation result
+type simulatedSolverOperation struct {
+	types.SolverOperationRaw
+	Simulation *struct {
+		Success       bool         `json:"success"`
+		Result        uint8        `json:"result"`
+		SolverOutcome *hexutil.Big `json:"solver_outcome"`
+		Error         string       `json:"error"`
+	} `json:"simulation"`
+}
+
+// TestSimulationMark expects every solver operation returned to carry its simulation result, the solver operations
+// past the limit not to be simulated and the results to be reused within a block
+func TestSimulationMark(t *testing.T) {
+	r, chain, intentID, rejected := startSimulation(t, config.SimulationModeMark)
+
+	var ops []simulatedSolverOperation
+	r.decodeSolverOperations(t, intentID, url.Values{"sort": {"bid"}, "limit": {"1"}}, &ops)
+
+	// the solver operation of the rejected solver bids the most
+	if len(ops) != 1 || ops[0].From != rejected || ops[0].Simulation == nil || ops[0].Simulation.Success || ops[0].Simulation.Result != 9 {
+		t.Fatalf("expected the failed simulation of the rejected solver, got %+v", ops)
+	}
+
+	if simulations := chain.Simulations(); simulations != 1 {
+		t.Fatalf("expected the solver operation past the limit not to be simulated, got %d simulations", simulations)
+	}
+
+	r.decodeSolverOperations(t, intentID, url.Values{"sort": {"bid"}}, &ops)
+
+	if len(ops) != 2 || ops[1].Simulation == nil || !ops[1].Simulation.Success {
+		t.Fatalf("expected the successful simulation of the other solver, got %+v", ops)
+	}
+
+	if simulations := chain.Simulations(); simulations != 2 {
+		t.Fatalf("expected the simulation result to be reused, got %d simulations", simulations)
+	}
+}
+
+// TestSimulationFilter expects the solver operations failing the simulation to be dropped before the limit applies
+func TestSimulationFilter(t *testing.T) {
+	r, _, intentID, rejected := startSimulation(t, config.SimulationModeFilter)
+
+	var ops []simulatedSolverOperation
+	r.decodeSolverOperations(t, intentID, url.Values{"sort": {"bid"}, "limit": {"1"}}, &ops)
+
+	if len(ops) != 1 || ops[0].From == rejected || ops[0].Simulation == nil || !ops[0].Simulation.Success {
+		t.Fatalf("expected the solver operation passing the simulation, got %+v", ops)
+	}
+
+	// the dApp socket filters the solver operations as /solverOperations does
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn := r.dialDAppRPC(t, &notificationHandler{notifications: make(chan json.RawMessage, 10)})
+	if err := conn.Call(ctx, "getSolverOperations", map[string]interface{}{"intent_id": intentID, "sort": "bid"}, &ops); err != nil {
+		t.Fatalf("failed to get solver operations: %v", err)
+	}
+
+	if len(ops) != 1 || ops[0].From == rejected || ops[0].Simulation == nil || !ops[0].Simulation.Success {
+		t.Fatalf("expected the dApp socket to drop the solver operation failing the simulation, got %+v", ops)
+	}
+}
+
+// startSimulation starts a relay simulating the solver operations in the mode and submits an intent with two
+// solver operations. The one of the rejected solver bids more and fails the simulation
+func startSimulation(t *testing.T, mode config.SimulationMode) (*relay, *fakechain.Chain, string, common.Address) {
+	t.Helper()
+
+	chain := newChain(t, 1_000)
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Atlas.EthRPCURL = chain.URL()
+		cfg.Atlas.SimulationMode = mode
+	})
+
+	intents := r.subscribeToIntents(t)
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	rejectedKey := newKey(t)
+	rejected := crypto.PubkeyToAddress(rejectedKey.PublicKey)
+	chain.RejectSolver(rejected)
+
+	solverOps := []*types.SolverOperation{
+		newSolverOperationWithBid(t, r.solverKey, partialUserOp, 1_000),
+		newSolverOperationWithBid(t, rejectedKey, partialUserOp, 2_000),
+	}
+
+	// querying the solver operations would simulate them, the solutions subscription of the dApp socket
+	// tells when both were received
+	h := &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+	r.subscribe(t, r.dialDAppRPC(t, h), map[string]interface{}{"subscription_type": "solutions", "intent_id": intentID})
+
+	for _, op := range solverOps {
+		r.submitSolverOperation(t, intentID, op)
+	}
+
+	for range solverOps {
+		select {
+		case <-h.notifications:
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for the solver operations")
+		}
+	}
+
+	return r, chain, intentID, rejected
+}
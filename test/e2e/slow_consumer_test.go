@@ -0,0 +1,164 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/relay/service"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestSlowConsumerPolicies fills the buffer of a subscription that is not read
+// and expects each policy to keep, drop or evict as configured
+func TestSlowConsumerPolicies(t *testing.T) {
+	tests := []struct {
+		policy  config.SlowConsumerPolicy
+		kept    []string
+		dropped uint64
+		evicted bool
+	}{
+		{config.SlowConsumerPolicyDropNewest, []string{"0", "1"}, 3, false},
+		{config.SlowConsumerPolicyDropOldest, []string{"3", "4"}, 3, false},
+		{config.SlowConsumerPolicyDisconnect, []string{"0", "1"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			manager := service.NewSubscriptionManager(config.SubscriptionsConfig{
+				BufferSize:         2,
+				SlowConsumerPolicy: tt.policy,
+			})
+			t.Cleanup(manager.Close)
+
+			subscription, evicted := subscribeSlowConsumer(t, manager)
+
+			for i := 0; i < 5; i++ {
+				manager.NotifyIntent(config.DefaultSolverName, &sdk.OnIntentsNotification{IntentID: fmt.Sprint(i)})
+			}
+
+			select {
+			case <-evicted:
+				if !tt.evicted {
+					t.Fatal("unexpected eviction")
+				}
+			default:
+				if tt.evicted {
+					t.Fatal("expected the client to be evicted")
+				}
+			}
+
+			if subscription.Dropped() != tt.dropped {
+				t.Fatalf("expected %d dropped notifications, got %d", tt.dropped, subscription.Dropped())
+			}
+
+			for _, intentID := range tt.kept {
+				msg := <-subscription.NotificationChannel
+				if intent, ok := msg.(*service.IntentNotification); !ok || intent.IntentID != intentID {
+					t.Fatalf("expected intent %s to be kept, got %+v", intentID, msg)
+				}
+			}
+		})
+	}
+}
+
+// TestSlowConsumerMaxLag expects the client of a subscription whose buffer overflowed to be evicted
+// once it did not drain it within the max lag, and the clients that drained it to be kept
+func TestSlowConsumerMaxLag(t *testing.T) {
+	manager := service.NewSubscriptionManager(config.SubscriptionsConfig{
+		BufferSize: 1,
+		MaxLag:     50 * time.Millisecond,
+	})
+	t.Cleanup(manager.Close)
+
+	lagging, laggingEvicted := subscribeSlowConsumer(t, manager)
+	drained, drainedEvicted := subscribeSlowConsumer(t, manager)
+
+	for i := 0; i < 2; i++ {
+		manager.NotifyIntent(config.DefaultSolverName, &sdk.OnIntentsNotification{IntentID: fmt.Sprint(i)})
+	}
+
+	<-drained.NotificationChannel
+	drained.Delivered()
+
+	select {
+	case <-laggingEvicted:
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the lagging client to be evicted")
+	}
+
+	select {
+	case <-drainedEvicted:
+		t.Fatal("the client that drained its buffer was evicted")
+	default:
+	}
+
+	if lagging.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped notification, got %d", lagging.Dropped())
+	}
+}
+
+// subscribeSlowConsumer registers a connection with an intent subscription, the returned channel is closed
+// once the connection is evicted
+func subscribeSlowConsumer(t *testing.T, manager *service.SubscriptionManager) (*service.Subscription, <-chan struct{}) {
+	t.Helper()
+
+	evicted := make(chan struct{})
+
+	connectionID := manager.Connect(service.ConnectionInfo{
+		Kind:   service.ConnectionKindSolverSocket,
+		Solver: config.DefaultSolverName,
+	}, sync.OnceFunc(func() { close(evicted) }))
+
+	subscription, err := manager.Subscribe(connectionID, service.SubscriptionTypeIntent,
+		service.SubscriptionFilter{Solver: config.DefaultSolverName}, "", 0)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	return subscription, evicted
+}
+
+// TestSubscriptionStatusNotification expects the solver socket subscriptions to get their periodic status
+func TestSubscriptionStatusNotification(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Subscriptions.StatusInterval = 50 * time.Millisecond
+	})
+
+	statuses := make(chan json.RawMessage, 10)
+
+	conn := r.dialSolverRPC(t, jsonrpc2.HandlerWithError(
+		func(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+			if req.Method == "subscriptionStatus" && req.Params != nil {
+				statuses <- *req.Params
+			}
+			return nil, nil
+		},
+	))
+
+	subscriptionID := r.subscribe(t, conn, map[string]interface{}{"subscription_type": "intent"})
+
+	var status service.SubscriptionStatus
+
+	select {
+	case msg := <-statuses:
+		if err := json.Unmarshal(msg, &status); err != nil {
+			t.Fatalf("failed to decode subscription status: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the subscription status")
+	}
+
+	if status.SubscriptionID != subscriptionID || status.Dropped != 0 || status.BufferSize != config.DefaultSubscriptionBufferSize ||
+		status.SlowConsumerPolicy != config.SlowConsumerPolicyDropNewest {
+		t.Fatalf("unexpected subscription status %+v", status)
+	}
+}
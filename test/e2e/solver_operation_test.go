@@ -0,0 +1,90 @@
+package e2e
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/FastLane-Labs/atlas-sdk-go/utils"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestSolverOperationRejected expects the solver operations that are malformed or not signed by their from address
+// to be rejected with their error codes before reaching the BDN
+func TestSolverOperationRejected(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	intents := r.subscribeToIntents(t)
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	// sign signs the operation again with key for the domain of the chain
+	sign := func(op *types.SolverOperation, key *ecdsa.PrivateKey, chainID uint64) {
+		hash, err := op.Hash(chainID)
+		if err != nil {
+			t.Fatalf("failed to hash solver operation: %v", err)
+		}
+
+		op.Signature, err = utils.SignMessage(hash.Bytes(), key)
+		if err != nil {
+			t.Fatalf("failed to sign solver operation: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		solverOp func(op *types.SolverOperation) interface{}
+		code     int64
+	}{
+		{"signed by another key", func(op *types.SolverOperation) interface{} {
+			sign(op, newKey(t), chainID)
+			return op.EncodeToRaw()
+		}, -32002},
+		{"changed after signing", func(op *types.SolverOperation) interface{} {
+			op.BidAmount = big.NewInt(2_000)
+			return op.EncodeToRaw()
+		}, -32002},
+		{"signed for another chain", func(op *types.SolverOperation) interface{} {
+			// BNB Smart Chain
+			sign(op, r.solverKey, 56)
+			return op.EncodeToRaw()
+		}, -32002},
+		{"missing from", func(op *types.SolverOperation) interface{} {
+			raw := op.EncodeToRaw()
+			raw.From = [20]byte{}
+			return raw
+		}, -32001},
+		{"short signature", func(op *types.SolverOperation) interface{} {
+			op.Signature = op.Signature[:32]
+			return op.EncodeToRaw()
+		}, -32001},
+		{"invalid field", func(*types.SolverOperation) interface{} {
+			return map[string]string{"from": crypto.PubkeyToAddress(r.solverKey.PublicKey).Hex(), "gas": "not a number"}
+		}, -32001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			solution := tt.solverOp(newSolverOperation(t, r.solverKey, partialUserOp))
+
+			_, err := r.callSubmitSolverOperation(t, intentID, solution)
+
+			var rpcErr *jsonrpc2.Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+				t.Fatalf("expected error code %d, got %v", tt.code, err)
+			}
+		})
+	}
+
+	if solutions := r.gateway.Solutions(intentID); len(solutions) != 0 {
+		t.Fatalf("expected no solution to reach the gateway, got %d", len(solutions))
+	}
+}
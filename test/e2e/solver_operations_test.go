@@ -0,0 +1,299 @@
+package e2e
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestWaitForSolverOperations expects wait_ms to hold /solverOperations until min_solutions solver operations
+// were received, and to return the ones received so far when the wait is over
+func TestWaitForSolverOperations(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	intents := r.subscribeToIntents(t)
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	start := time.Now()
+	if ops := r.querySolverOperations(t, intentID, url.Values{"wait_ms": {"300"}}); len(ops) != 0 {
+		t.Fatalf("expected no solver operations, got %d", len(ops))
+	}
+
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("expected the request to wait 300ms, it returned after %v", elapsed)
+	}
+
+	// the solver operations are signed up front, the EIP-712 hashing of the Atlas SDK is not safe to run
+	// concurrently with the relay hashing for the waiting request
+	first := newSolverOperationWithBid(t, r.solverKey, partialUserOp, 1_000)
+	second := newSolverOperationWithBid(t, r.solverKey, partialUserOp, 2_000)
+
+	waited := make(chan []types.SolverOperationRaw, 1)
+	go func() {
+		waited <- r.querySolverOperations(t, intentID, url.Values{
+			"wait_ms":       {fmt.Sprint(timeout.Milliseconds())},
+			"min_solutions": {"2"},
+		})
+	}()
+
+	r.submitSolverOperation(t, intentID, first)
+
+	select {
+	case ops := <-waited:
+		t.Fatalf("expected the request to wait for 2 solver operations, got %d", len(ops))
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	r.submitSolverOperation(t, intentID, second)
+
+	select {
+	case ops := <-waited:
+		if len(ops) != 2 {
+			t.Fatalf("expected 2 solver operations, got %d", len(ops))
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the solver operations")
+	}
+}
+
+// TestStreamSolverOperations expects the solver operations stream to send the solver operations received
+// before the client connected, then each new one as it arrives
+func TestStreamSolverOperations(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	intents := r.subscribeToIntents(t)
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	first := newSolverOperationWithBid(t, r.solverKey, partialUserOp, 1_000)
+	second := newSolverOperationWithBid(t, r.solverKey, partialUserOp, 2_000)
+
+	r.submitSolverOperation(t, intentID, first)
+	r.solverOperations(t, intentID)
+
+	events := r.streamEvents(t, intentID)
+
+	r.submitSolverOperation(t, intentID, second)
+
+	for _, expected := range []*types.SolverOperation{first, second} {
+		var event sseEvent
+		select {
+		case event = <-events:
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for the solver operation event")
+		}
+
+		var op types.SolverOperationRaw
+		if err := json.Unmarshal([]byte(event.data), &op); err != nil {
+			t.Fatalf("failed to decode solver operation event: %v", err)
+		}
+
+		if event.name != "solverOperation" || op.BidAmount.ToInt().Cmp(expected.BidAmount) != 0 {
+			t.Fatalf("unexpected event %s with bid %s, expected bid %s", event.name, op.BidAmount, expected.BidAmount)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/solverOperations/stream?intent_id=unknown-intent", nil)
+	if err != nil {
+		t.Fatalf("failed to create stream request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to stream solver operations: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 streaming an unknown intent, got %d", resp.StatusCode)
+	}
+}
+
+// TestDuplicateSolverOperations expects a solver operation delivered twice by the BDN to be kept once
+func TestDuplicateSolverOperations(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	intents := r.subscribeToIntents(t)
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	first := newSolverOperationWithBid(t, r.solverKey, partialUserOp, 1_000)
+	second := newSolverOperationWithBid(t, r.solverKey, partialUserOp, 2_000)
+
+	// the gateway delivers each submission, the duplicate is received before the second solver operation
+	r.submitSolverOperation(t, intentID, first)
+	r.submitSolverOperation(t, intentID, first)
+	r.submitSolverOperation(t, intentID, second)
+
+	if solutions := r.gateway.Solutions(intentID); len(solutions) != 3 {
+		t.Fatalf("expected the gateway to get 3 solutions, got %d", len(solutions))
+	}
+
+	ops := r.querySolverOperations(t, intentID, url.Values{
+		"wait_ms":       {fmt.Sprint(timeout.Milliseconds())},
+		"min_solutions": {"2"},
+	})
+	if len(ops) != 2 || ops[0].BidAmount.ToInt().Cmp(first.BidAmount) != 0 || ops[1].BidAmount.ToInt().Cmp(second.BidAmount) != 0 {
+		t.Fatalf("unexpected solver operations %+v", ops)
+	}
+}
+
+// TestExpiredSolverOperations expects the solver operations past their deadline block to be left out,
+// a zero deadline is no deadline
+func TestExpiredSolverOperations(t *testing.T) {
+	chain := newChain(t, 1_000)
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Atlas.EthRPCURL = chain.URL()
+	})
+
+	intents := r.subscribeToIntents(t)
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+	intentID, partialUserOp := receiveIntent(t, intents)
+
+	// the bid tells the solver operations apart. They are all signed before the first one is submitted,
+	// the EIP-712 hashing of the Atlas SDK is not safe to run concurrently with the relay hashing the received ones
+	deadlines := map[int64]int64{1_000: 0, 2_000: 999, 3_000: 1_000, 4_000: 1_005}
+
+	solverOps := make([]*types.SolverOperation, 0, len(deadlines))
+	for bid, deadline := range deadlines {
+		op := newSolverOperationWithBid(t, r.solverKey, partialUserOp, bid)
+		op.Deadline = big.NewInt(deadline)
+		signSolverOperation(t, op, r.solverKey)
+
+		solverOps = append(solverOps, op)
+	}
+
+	for _, op := range solverOps {
+		r.submitSolverOperation(t, intentID, op)
+	}
+
+	ops := r.querySolverOperations(t, intentID, url.Values{
+		"wait_ms":       {fmt.Sprint(timeout.Milliseconds())},
+		"min_solutions": {fmt.Sprint(len(deadlines))},
+		"sort":          {"bid"},
+	})
+
+	var got []int64
+	for _, op := range ops {
+		got = append(got, deadlines[op.BidAmount.ToInt().Int64()])
+	}
+
+	if len(got) != 3 || got[0] != 1_005 || got[1] != 1_000 || got[2] != 0 {
+		t.Fatalf("expected the solver operations with deadlines 1005, 1000 and 0, got %v", got)
+	}
+}
+
+type sseEvent struct {
+	name string
+	data string
+}
+
+// streamEvents reads the Server-Sent Events of the solver operations stream of the intent
+func (r *relay) streamEvents(t *testing.T, intentID string) <-chan sseEvent {
+	t.Helper()
+
+	q := url.Values{}
+	q.Set("intent_id", intentID)
+
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/solverOperations/stream?"+q.Encode(), nil)
+	if err != nil {
+		t.Fatalf("failed to create stream request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to stream solver operations: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d streaming solver operations", resp.StatusCode)
+	}
+
+	events := make(chan sseEvent, 10)
+
+	go func() {
+		defer close(events)
+
+		var event sseEvent
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			case line == "" && event.name != "":
+				events <- event
+				event = sseEvent{}
+			}
+		}
+	}()
+
+	return events
+}
+
+// querySolverOperations gets the solver operations of the intent with the query parameters
+func (r *relay) querySolverOperations(t *testing.T, intentID string, q url.Values) []types.SolverOperationRaw {
+	t.Helper()
+
+	var ops []types.SolverOperationRaw
+	r.decodeSolverOperations(t, intentID, q, &ops)
+
+	return ops
+}
+
+// decodeSolverOperations gets the solver operations of the intent with the query parameters into v,
+// it may be called from another goroutine than the test
+func (r *relay) decodeSolverOperations(t *testing.T, intentID string, q url.Values, v interface{}) {
+	t.Helper()
+
+	q.Set("intent_id", intentID)
+
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/solverOperations?"+q.Encode(), nil)
+	if err != nil {
+		t.Errorf("failed to create request: %v", err)
+		return
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Errorf("failed to get solver operations: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected status %d getting solver operations", resp.StatusCode)
+		return
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Errorf("failed to decode solver operations: %v", err)
+	}
+}
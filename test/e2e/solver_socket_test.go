@@ -0,0 +1,103 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestSolverSocketConcurrentCalls runs two intent subscriptions on a single solver socket
+// and expects the unsubscribe and submitSolverOperation calls on the same socket to be answered
+func TestSolverSocketConcurrentCalls(t *testing.T) {
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	})
+
+	h := &notificationHandler{
+		notifications: make(chan json.RawMessage, 10),
+	}
+
+	conn := r.dialSolverRPC(t, h)
+
+	userOp := newUserOperation(r.dAppKey)
+
+	allIntents := r.subscribe(t, conn, map[string]interface{}{"subscription_type": "intent"})
+	dAppIntents := r.subscribe(t, conn, map[string]interface{}{
+		"subscription_type": "intent",
+		"filters":           map[string]string{"dapp_address": userOp.Dapp.Hex()},
+	})
+
+	if allIntents == dAppIntents {
+		t.Fatalf("expected two subscriptions, got %s twice", allIntents)
+	}
+
+	intentID := r.submitUserOperation(t, userOp)
+
+	var intent struct {
+		IntentID string `json:"intentID"`
+		Intent   []byte `json:"intent"`
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-h.notifications:
+			if err := json.Unmarshal(msg, &intent); err != nil {
+				t.Fatalf("failed to decode intent notification: %v", err)
+			}
+
+			if intent.IntentID != intentID {
+				t.Fatalf("received intent %s, expected %s", intent.IntentID, intentID)
+			}
+		case <-time.After(timeout):
+			t.Fatalf("timed out waiting for the intent notification of subscription %d", i+1)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var unsubscribed string
+	if err := conn.Call(ctx, "unsubscribe", map[string]string{"subscription_id": dAppIntents}, &unsubscribed); err != nil {
+		t.Fatalf("failed to unsubscribe: %v", err)
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	var submitted submittedSolverOperation
+
+	err := conn.Call(ctx, "submitSolverOperation", map[string]interface{}{
+		"intent_id":       intentID,
+		"intent_solution": newSolverOperation(t, r.solverKey, &partialUserOp).EncodeToRaw(),
+	}, &submitted)
+	if err != nil {
+		t.Fatalf("failed to submit solver operation: %v", err)
+	}
+
+	if submitted.SolutionID == "" {
+		t.Fatalf("unexpected acknowledgement %+v", submitted)
+	}
+
+	// only the remaining subscription gets the next intent
+	r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	select {
+	case <-h.notifications:
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	select {
+	case msg := <-h.notifications:
+		t.Fatalf("unexpected notification after unsubscribing: %s", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,211 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+func TestMultipleSolvers(t *testing.T) {
+	alphaKey := newKey(t)
+	betaKey := newKey(t)
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Solvers = []config.SolverConfig{
+			{Name: "alpha", PrivateKey: hexutil.Encode(crypto.FromECDSA(alphaKey))[2:]},
+			{Name: "beta", PrivateKey: hexutil.Encode(crypto.FromECDSA(betaKey))[2:]},
+		}
+		cfg.Auth = config.AuthConfig{
+			APIKeys: []config.APIKeyConfig{
+				{Name: "dapp", Key: "dapp-key", Permissions: []config.Permission{config.PermissionDApp}},
+				{Name: "alpha", Key: "alpha-key", Permissions: []config.Permission{config.PermissionSolver}, Solver: "alpha"},
+				{Name: "beta", Key: "beta-key", Permissions: []config.Permission{config.PermissionSolver}, Solver: "beta"},
+			},
+		}
+	})
+
+	r.apiKey = "alpha-key"
+	alphaIntents := r.subscribeToIntents(t)
+	r.apiKey = "beta-key"
+	betaIntents := r.subscribeToIntents(t)
+
+	r.apiKey = "dapp-key"
+	intentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	var intent struct {
+		IntentID string `json:"intentID"`
+		Intent   []byte `json:"intent"`
+	}
+
+	for name, intents := range map[string]<-chan json.RawMessage{"alpha": alphaIntents, "beta": betaIntents} {
+		select {
+		case msg := <-intents:
+			if err := json.Unmarshal(msg, &intent); err != nil {
+				t.Fatalf("failed to decode intent notification: %v", err)
+			}
+		case <-time.After(timeout):
+			t.Fatalf("timed out waiting for the intent notification of solver %s", name)
+		}
+
+		if intent.IntentID != intentID {
+			t.Fatalf("expected intent %s for solver %s, got %s", intentID, name, intent.IntentID)
+		}
+
+		// every solver has its own intents subscription, the intent must not be delivered twice
+		select {
+		case <-intents:
+			t.Fatalf("solver %s received the intent more than once", name)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	// the solutions subscriptions only receive the solver operations of their own solver
+	solutions := make(map[string]<-chan json.RawMessage, 2)
+	for _, name := range []string{"alpha", "beta"} {
+		h := &notificationHandler{
+			notifications: make(chan json.RawMessage, 10),
+		}
+
+		r.apiKey = name + "-key"
+		r.subscribe(t, r.dialSolverRPC(t, h), map[string]interface{}{"subscription_type": "intentSolution"})
+		solutions[name] = h.notifications
+	}
+
+	r.apiKey = "beta-key"
+	r.submitSolverOperation(t, intentID, newSolverOperation(t, betaKey, &partialUserOp))
+
+	var solution struct {
+		IntentID        string                   `json:"intent_id"`
+		SolverOperation types.SolverOperationRaw `json:"solver_operation"`
+	}
+
+	select {
+	case msg := <-solutions["beta"]:
+		if err := json.Unmarshal(msg, &solution); err != nil {
+			t.Fatalf("failed to decode solution notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the solution notification of solver beta")
+	}
+
+	if solution.IntentID != intentID || solution.SolverOperation.From != crypto.PubkeyToAddress(betaKey.PublicKey) {
+		t.Fatalf("unexpected solution notification %+v", solution)
+	}
+
+	select {
+	case msg := <-solutions["alpha"]:
+		t.Fatalf("solver alpha received the solver operation of solver beta: %s", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	r.apiKey = "dapp-key"
+	if ops := r.solverOperations(t, intentID); len(ops) != 1 {
+		t.Fatalf("expected 1 solver operation, got %d", len(ops))
+	}
+
+	submitted := r.gateway.Solutions(intentID)
+	if len(submitted) != 1 {
+		t.Fatalf("expected the gateway to get 1 solution, got %d", len(submitted))
+	}
+
+	if submitted[0].SolverAddress != crypto.PubkeyToAddress(betaKey.PublicKey).Hex() {
+		t.Fatalf("solution was not submitted with the key of solver beta")
+	}
+}
+
+// TestSolverDAppAddresses expects every solver to receive the intents of the dApps of its own dApp addresses only
+func TestSolverDAppAddresses(t *testing.T) {
+	otherAddress := common.HexToAddress("0x02").Hex()
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.DApps = []config.DAppConfig{
+			{Name: "other", PrivateKey: hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:], Address: otherAddress},
+		}
+		cfg.Solvers = []config.SolverConfig{
+			{Name: "alpha", PrivateKey: hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:], DAppAddresses: []string{cfg.DAppAddress, otherAddress}},
+			// beta subscribes to the intents of the top-level dApp address
+			{Name: "beta", PrivateKey: hexutil.Encode(crypto.FromECDSA(newKey(t)))[2:]},
+		}
+		cfg.Auth = config.AuthConfig{
+			APIKeys: []config.APIKeyConfig{
+				{Name: "dapp", Key: "dapp-key", Permissions: []config.Permission{config.PermissionDApp}, DApps: []string{config.DefaultDAppName, "other"}},
+				{Name: "alpha", Key: "alpha-key", Permissions: []config.Permission{config.PermissionSolver}, Solver: "alpha"},
+				{Name: "beta", Key: "beta-key", Permissions: []config.Permission{config.PermissionSolver}, Solver: "beta"},
+			},
+		}
+	})
+
+	r.apiKey = "alpha-key"
+	alphaIntents := r.subscribeToIntents(t)
+	r.apiKey = "beta-key"
+	betaIntents := r.subscribeToIntents(t)
+
+	r.apiKey = "dapp-key"
+	defaultIntentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	userOp := newUserOperation(r.dAppKey)
+	body, err := json.Marshal(types.NewUserOperationWithHintsRaw(chainID, userOp, []common.Address{userOp.To}))
+	if err != nil {
+		t.Fatalf("failed to marshal user operation: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.baseURL+"/dapp/other/userOperation", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(r.withAPIKey(req))
+	if err != nil {
+		t.Fatalf("failed to submit user operation: %v", err)
+	}
+
+	var submitted struct {
+		IntentID string `json:"intent_id"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&submitted)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to decode user operation response: %v", err)
+	}
+
+	received := make(map[string]struct{}, 2)
+	for range 2 {
+		intentID, _ := receiveIntent(t, alphaIntents)
+		received[intentID] = struct{}{}
+	}
+
+	if _, ok := received[defaultIntentID]; !ok {
+		t.Fatal("solver alpha did not receive the intent of the top-level dApp")
+	}
+
+	if _, ok := received[submitted.IntentID]; !ok {
+		t.Fatal("solver alpha did not receive the intent of the other dApp")
+	}
+
+	if intentID, _ := receiveIntent(t, betaIntents); intentID != defaultIntentID {
+		t.Fatalf("expected solver beta to receive intent %s, got %s", defaultIntentID, intentID)
+	}
+
+	select {
+	case msg := <-betaIntents:
+		t.Fatalf("solver beta received the intent of a dApp it did not subscribe to: %s", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,77 @@
+package e2e
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestStoreSurvivesRestart expects a relay restarted on the same LevelDB store, and connected to a gateway
+// which never saw the intent, to still return its solver operations, even with auctions enabled since
+// the intent was not auctioned by the restarted relay
+func TestStoreSurvivesRestart(t *testing.T) {
+	storeCfg := config.StoreConfig{
+		Type: config.StoreTypeLevelDB,
+		Path: filepath.Join(t.TempDir(), "store"),
+	}
+
+	r := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Store = storeCfg
+	})
+
+	intents := r.subscribeToIntents(t)
+	intentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	var intent struct {
+		Intent []byte `json:"intent"`
+	}
+
+	select {
+	case msg := <-intents:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	solverOp := newSolverOperation(t, r.solverKey, &partialUserOp)
+	r.submitSolverOperation(t, intentID, solverOp)
+
+	if ops := r.solverOperations(t, intentID); len(ops) != 1 {
+		t.Fatalf("expected 1 solver operation before the restart, got %d", len(ops))
+	}
+
+	r.stop()
+
+	restarted := startRelay(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+		cfg.Store = storeCfg
+		cfg.DAppPrivateKey = hexutil.Encode(crypto.FromECDSA(r.dAppKey))[2:]
+		cfg.DAppAddress = crypto.PubkeyToAddress(r.dAppKey.PublicKey).Hex()
+		cfg.Auction = config.AuctionConfig{DurationMS: 1000}
+	})
+
+	ops := restarted.solverOperations(t, intentID)
+	if len(ops) != 1 {
+		t.Fatalf("expected 1 solver operation after the restart, got %d", len(ops))
+	}
+
+	if ops[0].From != solverOp.From {
+		t.Fatalf("unexpected solver operation %+v", ops[0])
+	}
+}
@@ -0,0 +1,94 @@
+package e2e
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/bloXroute-Labs/bdn-operations-relay/config"
+	"github.com/bloXroute-Labs/bdn-operations-relay/test/fakegateway"
+)
+
+// TestSubmitSolverOperationReplyWS expects the intents unknown to the BDN to be reported as rejected by the BDN,
+// the JSON-RPC errors of the gateway do not tell an unknown intent apart
+func TestSubmitSolverOperationReplyWS(t *testing.T) {
+	testSubmitSolverOperationReply(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.WSURL = gateway.WSURL()
+	}, -32006)
+}
+
+// TestSubmitSolverOperationReplyGRPC expects the NOT_FOUND status of the gateway to be reported as an unknown intent
+func TestSubmitSolverOperationReplyGRPC(t *testing.T) {
+	testSubmitSolverOperationReply(t, func(cfg *config.Config, gateway *fakegateway.Gateway) {
+		cfg.BDN.GRPCURL = gateway.GRPCURL()
+		cfg.BDN.GRPCInsecure = true
+	}, -32004)
+}
+
+// testSubmitSolverOperationReply expects submitSolverOperation to be acknowledged with the BDN solution id
+// and its failures to be reported with their stable error codes
+func testSubmitSolverOperationReply(t *testing.T, withBDN func(*config.Config, *fakegateway.Gateway), unknownIntentCode int64) {
+	r := startRelay(t, withBDN)
+
+	intents := r.subscribeToIntents(t)
+	intentID := r.submitUserOperation(t, newUserOperation(r.dAppKey))
+
+	var intent struct {
+		Intent []byte `json:"intent"`
+	}
+
+	select {
+	case msg := <-intents:
+		if err := json.Unmarshal(msg, &intent); err != nil {
+			t.Fatalf("failed to decode intent notification: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the intent notification")
+	}
+
+	var partialUserOp types.UserOperationPartialRaw
+	if err := json.Unmarshal(intent.Intent, &partialUserOp); err != nil {
+		t.Fatalf("failed to decode intent: %v", err)
+	}
+
+	solverOp := newSolverOperation(t, r.solverKey, &partialUserOp)
+
+	submitted := r.submitSolverOperation(t, intentID, solverOp)
+
+	solutions := r.gateway.Solutions(intentID)
+	if len(solutions) != 1 || submitted.SolutionID != solutions[0].ID {
+		t.Fatalf("expected the solution id of the BDN, got %+v", submitted)
+	}
+
+	if submitted.AcceptedAt.IsZero() || submitted.SolverOperationHash == (common.Hash{}) {
+		t.Fatalf("unexpected acknowledgement %+v", submitted)
+	}
+
+	tests := []struct {
+		name     string
+		intentID string
+		solution interface{}
+		code     int64
+	}{
+		{"missing intent solution", intentID, nil, jsonrpc2.CodeInvalidParams},
+		{"malformed solver operation", intentID, map[string]string{"from": "not an address"}, -32001},
+		// the relay forwards the solutions of the intents it did not deliver, the BDN rejects them
+		{"intent unknown to the BDN", "unknown-intent", solverOp.EncodeToRaw(), unknownIntentCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.callSubmitSolverOperation(t, tt.intentID, tt.solution)
+
+			var rpcErr *jsonrpc2.Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+				t.Fatalf("expected error code %d, got %v", tt.code, err)
+			}
+		})
+	}
+}
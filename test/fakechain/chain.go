@@ -0,0 +1,155 @@
+// Package fakechain is an in-memory stand-in of the Atlas chain node answering eth_blockNumber
+// and the eth_call of the Atlas simulator, it lets the deadline and simulation features be exercised end to end
+package fakechain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/FastLane-Labs/atlas-sdk-go/contract/simulator"
+	"github.com/FastLane-Labs/atlas-sdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const simSolverCallMethod = "simSolverCall"
+
+// simulationFailure is the result code of the Atlas simulator returned for the rejected solvers
+const simulationFailure = 9
+
+var ErrUnsupportedCall = errors.New("only the simSolverCall method of the simulator is supported")
+
+// Chain serves the latest block number and the simulations of the solver operations from memory
+type Chain struct {
+	lock        sync.Mutex
+	blockNumber uint64
+	// rejected are the solvers whose solver operations fail the simulation
+	rejected map[common.Address]struct{}
+	// simulations counts the simSolverCall calls
+	simulations int
+
+	simulator *abi.ABI
+	rpc       *rpc.Server
+	http      *httptest.Server
+}
+
+// New starts a chain node at blockNumber listening on a local HTTP port
+func New(blockNumber uint64) (*Chain, error) {
+	simulatorABI, err := simulator.SimulatorMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse simulator ABI: %w", err)
+	}
+
+	c := &Chain{
+		blockNumber: blockNumber,
+		rejected:    make(map[common.Address]struct{}),
+		simulator:   simulatorABI,
+		rpc:         rpc.NewServer(),
+	}
+
+	err = c.rpc.RegisterName("eth", &ethAPI{chain: c})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register eth API: %w", err)
+	}
+
+	c.http = httptest.NewServer(c.rpc)
+
+	return c, nil
+}
+
+// URL returns the JSON-RPC URL of the node
+func (c *Chain) URL() string {
+	return c.http.URL
+}
+
+// SetBlockNumber sets the latest block number
+func (c *Chain) SetBlockNumber(blockNumber uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.blockNumber = blockNumber
+}
+
+// RejectSolver makes the solver operations of the solver fail the simulation
+func (c *Chain) RejectSolver(solver common.Address) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.rejected[solver] = struct{}{}
+}
+
+// Simulations returns the number of simulations run
+func (c *Chain) Simulations() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.simulations
+}
+
+// Close stops the node
+func (c *Chain) Close() {
+	c.http.Close()
+	c.rpc.Stop()
+}
+
+// simulate returns the encoded result of a simSolverCall of the simulator
+func (c *Chain) simulate(input []byte) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, ErrUnsupportedCall
+	}
+
+	method, err := c.simulator.MethodById(input[:4])
+	if err != nil || method.Name != simSolverCallMethod {
+		return nil, ErrUnsupportedCall
+	}
+
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil || len(args) != 3 {
+		return nil, fmt.Errorf("failed to unpack simulator call: %v", err)
+	}
+
+	solverOp, ok := abi.ConvertType(args[1], new(types.SolverOperation)).(*types.SolverOperation)
+	if !ok {
+		return nil, fmt.Errorf("unexpected solver operation %T", args[1])
+	}
+
+	c.lock.Lock()
+	c.simulations++
+	_, rejected := c.rejected[solverOp.From]
+	c.lock.Unlock()
+
+	if rejected {
+		return method.Outputs.Pack(false, uint8(simulationFailure), big.NewInt(1))
+	}
+
+	return method.Outputs.Pack(true, uint8(0), new(big.Int))
+}
+
+// ethAPI is the eth namespace of the JSON-RPC API of the node
+type ethAPI struct {
+	chain *Chain
+}
+
+// callArgs are the eth_call arguments used by the relay
+type callArgs struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	api.chain.lock.Lock()
+	defer api.chain.lock.Unlock()
+
+	return hexutil.Uint64(api.chain.blockNumber)
+}
+
+func (api *ethAPI) Call(args callArgs, _ json.RawMessage) (hexutil.Bytes, error) {
+	return api.chain.simulate(args.Input)
+}
@@ -0,0 +1,349 @@
+// Package fakegateway is an in-memory stand-in of a bloXroute gateway serving the intent API
+// over WS JSON-RPC and gRPC, it lets the relay be exercised end to end without a live BDN
+package fakegateway
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+)
+
+const subscriberChannelSize = 100
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrIntentNotFound   = errors.New("intent not found")
+)
+
+// Intent is an intent submitted to the gateway
+type Intent struct {
+	ID            string
+	DAppAddress   string
+	SenderAddress string
+	Payload       []byte
+	Timestamp     time.Time
+}
+
+// Solution is an intent solution submitted to the gateway
+type Solution struct {
+	ID            string
+	IntentID      string
+	SolverAddress string
+	Payload       []byte
+}
+
+// subscriber receives the intents or the solutions it is entitled to
+type subscriber struct {
+	// address is the solver address of an intents subscription or the dApp/sender address of a solutions subscription
+	address common.Address
+	// dAppFilter restricts an intents subscription to the intents of a dApp
+	dAppFilter string
+	ch         chan interface{}
+}
+
+// Gateway serves the intent API of a bloXroute gateway from memory
+type Gateway struct {
+	authHeader string
+
+	lock                sync.Mutex
+	intents             map[string]*Intent
+	solutions           map[string][]*Solution
+	intentSubscribers   map[*subscriber]struct{}
+	solutionSubscribers map[*subscriber]struct{}
+
+	// paused refuses the new WS connections
+	paused bool
+
+	ws           *httptest.Server
+	wsConns      map[*wsConn]struct{}
+	grpcServer   *grpc.Server
+	grpcListener net.Listener
+}
+
+// New starts a gateway listening on local WS and gRPC ports.
+// WS clients must send authHeader in the Authorization header, unless it is empty
+func New(authHeader string) (*Gateway, error) {
+	g := &Gateway{
+		authHeader:          authHeader,
+		intents:             make(map[string]*Intent),
+		solutions:           make(map[string][]*Solution),
+		intentSubscribers:   make(map[*subscriber]struct{}),
+		solutionSubscribers: make(map[*subscriber]struct{}),
+		wsConns:             make(map[*wsConn]struct{}),
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+
+	g.grpcListener = listener
+	g.grpcServer = grpc.NewServer()
+	pb.RegisterGatewayServer(g.grpcServer, &grpcGateway{gateway: g})
+
+	go func() {
+		_ = g.grpcServer.Serve(listener)
+	}()
+
+	g.ws = httptest.NewServer(g.wsHandler())
+
+	return g, nil
+}
+
+// WSURL returns the URL of the WS JSON-RPC endpoint
+func (g *Gateway) WSURL() string {
+	return "ws" + strings.TrimPrefix(g.ws.URL, "http") + "/ws"
+}
+
+// GRPCURL returns the address of the gRPC endpoint, it is served without TLS
+func (g *Gateway) GRPCURL() string {
+	return g.grpcListener.Addr().String()
+}
+
+// Intents returns the intents submitted so far
+func (g *Gateway) Intents() []*Intent {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	intents := make([]*Intent, 0, len(g.intents))
+	for _, intent := range g.intents {
+		intents = append(intents, intent)
+	}
+
+	return intents
+}
+
+// Solutions returns the solutions submitted so far for the intent
+func (g *Gateway) Solutions(intentID string) []*Solution {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return append([]*Solution(nil), g.solutions[intentID]...)
+}
+
+// DropConnections closes all WS connections and gRPC streams, as a gateway restart would
+func (g *Gateway) DropConnections() {
+	g.lock.Lock()
+	conns := make([]*wsConn, 0, len(g.wsConns))
+	for conn := range g.wsConns {
+		conns = append(conns, conn)
+	}
+	for sub := range g.intentSubscribers {
+		close(sub.ch)
+		delete(g.intentSubscribers, sub)
+	}
+	for sub := range g.solutionSubscribers {
+		close(sub.ch)
+		delete(g.solutionSubscribers, sub)
+	}
+	g.lock.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+// RedeliverSolutions sends the solutions of the intent again to the solutions subscribers of the intent dApp and sender,
+// as the BDN may deliver a solution more than once
+func (g *Gateway) RedeliverSolutions(intentID string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return
+	}
+
+	for _, solution := range g.solutions[intentID] {
+		g.sendSolution(intent, solution)
+	}
+}
+
+// Pause refuses the new WS connections until Resume, with DropConnections it simulates a gateway outage
+func (g *Gateway) Pause() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.paused = true
+}
+
+// Resume accepts the new WS connections again
+func (g *Gateway) Resume() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.paused = false
+}
+
+// Close stops the gateway
+func (g *Gateway) Close() {
+	g.DropConnections()
+	g.grpcServer.Stop()
+	g.ws.Close()
+}
+
+// submitIntent stores the intent and sends it to the intents subscribers
+func (g *Gateway) submitIntent(dAppAddress, senderAddress string, payload, hash, signature []byte) (*Intent, error) {
+	err := verify(senderAddress, payload, hash, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	intent := &Intent{
+		ID:            uuid.New().String(),
+		DAppAddress:   dAppAddress,
+		SenderAddress: senderAddress,
+		Payload:       payload,
+		Timestamp:     time.Now(),
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.intents[intent.ID] = intent
+
+	for sub := range g.intentSubscribers {
+		if sub.dAppFilter != "" && !sameAddress(sub.dAppFilter, dAppAddress) {
+			continue
+		}
+
+		g.send(sub, intent)
+	}
+
+	return intent, nil
+}
+
+// submitSolution stores the solution and sends it to the solutions subscribers of the intent dApp and sender
+func (g *Gateway) submitSolution(solverAddress, intentID string, payload, hash, signature []byte) (*Solution, error) {
+	err := verify(solverAddress, payload, hash, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+
+	solution := &Solution{
+		ID:            uuid.New().String(),
+		IntentID:      intentID,
+		SolverAddress: solverAddress,
+		Payload:       payload,
+	}
+
+	g.solutions[intentID] = append(g.solutions[intentID], solution)
+	g.sendSolution(intent, solution)
+
+	return solution, nil
+}
+
+// sendSolution sends the solution to the solutions subscribers of the intent dApp and sender, must be called with the lock held
+func (g *Gateway) sendSolution(intent *Intent, solution *Solution) {
+	for sub := range g.solutionSubscribers {
+		if sameAddress(sub.address.Hex(), intent.DAppAddress) || sameAddress(sub.address.Hex(), intent.SenderAddress) {
+			g.send(sub, solution)
+		}
+	}
+}
+
+// getSolutions returns the solutions of an intent to its dApp or sender
+func (g *Gateway) getSolutions(address, intentID string, hash, signature []byte) ([]*Solution, error) {
+	err := verify(address, []byte(address+intentID), hash, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+
+	if !sameAddress(address, intent.DAppAddress) && !sameAddress(address, intent.SenderAddress) {
+		return nil, fmt.Errorf("%s is neither the dApp nor the sender of the intent", address)
+	}
+
+	return append([]*Solution(nil), g.solutions[intentID]...), nil
+}
+
+// subscribe registers a subscriber after checking it owns the address
+func (g *Gateway) subscribe(subscribers map[*subscriber]struct{}, address string, hash, signature []byte, dAppFilter string) (*subscriber, error) {
+	err := verify(address, []byte(address), hash, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &subscriber{
+		address:    common.HexToAddress(address),
+		dAppFilter: dAppFilter,
+		ch:         make(chan interface{}, subscriberChannelSize),
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// unsubscribe removes the subscriber and closes its channel, if it is still registered
+func (g *Gateway) unsubscribe(sub *subscriber) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, subscribers := range []map[*subscriber]struct{}{g.intentSubscribers, g.solutionSubscribers} {
+		if _, ok := subscribers[sub]; ok {
+			close(sub.ch)
+			delete(subscribers, sub)
+		}
+	}
+}
+
+// send delivers the message to the subscriber, must be called with the lock held
+func (g *Gateway) send(sub *subscriber, msg interface{}) {
+	select {
+	case sub.ch <- msg:
+	default:
+	}
+}
+
+// verify checks that the hash is the Keccak256 hash of the payload, signed by address
+func verify(address string, payload, hash, signature []byte) error {
+	if !bytes.Equal(crypto.Keccak256(payload), hash) {
+		return fmt.Errorf("%w: hash does not match the payload", ErrInvalidSignature)
+	}
+
+	pubKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	if !sameAddress(crypto.PubkeyToAddress(*pubKey).Hex(), address) {
+		return fmt.Errorf("%w: not signed by %s", ErrInvalidSignature, address)
+	}
+
+	return nil
+}
+
+func sameAddress(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
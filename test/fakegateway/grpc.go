@@ -0,0 +1,104 @@
+package fakegateway
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+)
+
+// grpcGateway serves the intent methods of the gateway gRPC API
+type grpcGateway struct {
+	pb.UnimplementedGatewayServer
+
+	gateway *Gateway
+}
+
+func (s *grpcGateway) SubmitIntent(_ context.Context, req *pb.SubmitIntentRequest) (*pb.SubmitIntentReply, error) {
+	intent, err := s.gateway.submitIntent(req.DappAddress, req.SenderAddress, req.Intent, req.Hash, req.Signature)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &pb.SubmitIntentReply{IntentId: intent.ID}, nil
+}
+
+func (s *grpcGateway) SubmitIntentSolution(_ context.Context, req *pb.SubmitIntentSolutionRequest) (*pb.SubmitIntentSolutionReply, error) {
+	solution, err := s.gateway.submitSolution(req.SolverAddress, req.IntentId, req.IntentSolution, req.Hash, req.Signature)
+	if errors.Is(err, ErrIntentNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &pb.SubmitIntentSolutionReply{SolutionId: solution.ID}, nil
+}
+
+func (s *grpcGateway) Intents(req *pb.IntentsRequest, stream pb.Gateway_IntentsServer) error {
+	sub, err := s.gateway.subscribe(s.gateway.intentSubscribers, req.SolverAddress, req.Hash, req.Signature,
+		strings.TrimPrefix(req.Filters, dAppFilterPrefix))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer s.gateway.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg, ok := <-sub.ch:
+			if !ok {
+				return status.Error(codes.Unavailable, "subscription closed")
+			}
+
+			intent := msg.(*Intent)
+
+			err = stream.Send(&pb.IntentsReply{
+				DappAddress:   intent.DAppAddress,
+				SenderAddress: intent.SenderAddress,
+				IntentId:      intent.ID,
+				Intent:        intent.Payload,
+				Timestamp:     timestamppb.New(intent.Timestamp),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *grpcGateway) IntentSolutions(req *pb.IntentSolutionsRequest, stream pb.Gateway_IntentSolutionsServer) error {
+	sub, err := s.gateway.subscribe(s.gateway.solutionSubscribers, req.DappAddress, req.Hash, req.Signature, "")
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer s.gateway.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg, ok := <-sub.ch:
+			if !ok {
+				return status.Error(codes.Unavailable, "subscription closed")
+			}
+
+			solution := msg.(*Solution)
+
+			err = stream.Send(&pb.IntentSolutionsReply{
+				IntentId:       solution.IntentID,
+				IntentSolution: solution.Payload,
+				SolutionId:     solution.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package fakegateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+	ws "github.com/sourcegraph/jsonrpc2/websocket"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+const dAppFilterPrefix = "dapp_address = "
+
+var upgrader = websocket.Upgrader{}
+
+type subscribeIntentsParams struct {
+	SolverAddress string `json:"solver_address"`
+	Hash          []byte `json:"hash"`
+	Signature     []byte `json:"signature"`
+	Filters       string `json:"filters"`
+}
+
+type subscribeSolutionsParams struct {
+	DAppAddress string `json:"dapp_address"`
+	Hash        []byte `json:"hash"`
+	Signature   []byte `json:"signature"`
+}
+
+type submitIntentParams struct {
+	DAppAddress   string `json:"dapp_address"`
+	SenderAddress string `json:"sender_address"`
+	Intent        []byte `json:"intent"`
+	Hash          []byte `json:"hash"`
+	Signature     []byte `json:"signature"`
+}
+
+type submitSolutionParams struct {
+	SolverAddress  string `json:"solver_address"`
+	IntentID       string `json:"intent_id"`
+	IntentSolution []byte `json:"intent_solution"`
+	Hash           []byte `json:"hash"`
+	Signature      []byte `json:"signature"`
+}
+
+type getSolutionsParams struct {
+	DAppOrSenderAddress string `json:"dapp_or_sender_address"`
+	IntentID            string `json:"intent_id"`
+	Hash                []byte `json:"hash"`
+	Signature           []byte `json:"signature"`
+}
+
+type intentNotification struct {
+	DAppAddress   string `json:"dapp_address"`
+	SenderAddress string `json:"sender_address"`
+	IntentID      string `json:"intent_id"`
+	Intent        []byte `json:"intent"`
+	Timestamp     string `json:"timestamp"`
+}
+
+type solutionNotification struct {
+	IntentID       string `json:"intent_id"`
+	IntentSolution []byte `json:"intent_solution"`
+	SolutionID     string `json:"solution_id"`
+	SolverAddress  string `json:"solver_address,omitempty"`
+}
+
+type subscriptionNotification struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+// wsConn is a JSON-RPC connection of a WS client and its subscriptions
+type wsConn struct {
+	*jsonrpc2.Conn
+
+	lock          sync.Mutex
+	subscriptions map[string]*subscriber
+}
+
+func (g *Gateway) wsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g.authHeader != "" && r.Header.Get("Authorization") != g.authHeader {
+			http.Error(w, "invalid auth header", http.StatusUnauthorized)
+			return
+		}
+
+		g.lock.Lock()
+		paused := g.paused
+		g.lock.Unlock()
+
+		if paused {
+			http.Error(w, "gateway is paused", http.StatusServiceUnavailable)
+			return
+		}
+
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		conn := &wsConn{
+			subscriptions: make(map[string]*subscriber),
+		}
+		conn.Conn = jsonrpc2.NewConn(context.Background(), ws.NewObjectStream(c), jsonrpc2.AsyncHandler(&wsHandler{gateway: g, conn: conn}))
+
+		g.lock.Lock()
+		g.wsConns[conn] = struct{}{}
+		g.lock.Unlock()
+
+		go func() {
+			<-conn.DisconnectNotify()
+
+			g.lock.Lock()
+			delete(g.wsConns, conn)
+			g.lock.Unlock()
+
+			conn.lock.Lock()
+			defer conn.lock.Unlock()
+
+			for id, sub := range conn.subscriptions {
+				g.unsubscribe(sub)
+				delete(conn.subscriptions, id)
+			}
+		}()
+	})
+}
+
+type wsHandler struct {
+	gateway *Gateway
+	conn    *wsConn
+}
+
+func (h *wsHandler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	result, err := h.handle(req)
+	if err != nil {
+		_ = conn.ReplyWithError(ctx, req.ID, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams, Message: err.Error()})
+		return
+	}
+
+	_ = conn.Reply(ctx, req.ID, result)
+}
+
+func (h *wsHandler) handle(req *jsonrpc2.Request) (interface{}, error) {
+	switch jsonrpc.RPCRequestType(req.Method) {
+	case jsonrpc.RPCSubscribe:
+		return h.subscribe(req)
+	case jsonrpc.RPCUnsubscribe:
+		return h.unsubscribe(req)
+	case jsonrpc.RPCSubmitIntent:
+		var params submitIntentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		intent, err := h.gateway.submitIntent(params.DAppAddress, params.SenderAddress, params.Intent, params.Hash, params.Signature)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]string{"intent_id": intent.ID}, nil
+	case jsonrpc.RPCSubmitIntentSolution:
+		var params submitSolutionParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		solution, err := h.gateway.submitSolution(params.SolverAddress, params.IntentID, params.IntentSolution, params.Hash, params.Signature)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]string{"solution_id": solution.ID}, nil
+	case jsonrpc.RPCGetIntentSolutions:
+		var params getSolutionsParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+
+		solutions, err := h.gateway.getSolutions(params.DAppOrSenderAddress, params.IntentID, params.Hash, params.Signature)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]solutionNotification, 0, len(solutions))
+		for _, solution := range solutions {
+			result = append(result, newSolutionNotification(solution))
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported method %s", req.Method)
+	}
+}
+
+func (h *wsHandler) subscribe(req *jsonrpc2.Request) (interface{}, error) {
+	var params []json.RawMessage
+	if err := unmarshalParams(req, &params); err != nil {
+		return nil, err
+	}
+
+	if len(params) != 2 {
+		return nil, fmt.Errorf("expected the feed name and the subscription options")
+	}
+
+	var feed types.FeedType
+	if err := json.Unmarshal(params[0], &feed); err != nil {
+		return nil, fmt.Errorf("invalid feed name: %v", err)
+	}
+
+	var (
+		sub *subscriber
+		err error
+	)
+
+	switch feed {
+	case types.UserIntentsFeed:
+		var opts subscribeIntentsParams
+		if err = json.Unmarshal(params[1], &opts); err != nil {
+			return nil, fmt.Errorf("invalid subscription options: %v", err)
+		}
+
+		sub, err = h.gateway.subscribe(h.gateway.intentSubscribers, opts.SolverAddress, opts.Hash, opts.Signature,
+			strings.TrimPrefix(opts.Filters, dAppFilterPrefix))
+	case types.UserIntentSolutionsFeed:
+		var opts subscribeSolutionsParams
+		if err = json.Unmarshal(params[1], &opts); err != nil {
+			return nil, fmt.Errorf("invalid subscription options: %v", err)
+		}
+
+		sub, err = h.gateway.subscribe(h.gateway.solutionSubscribers, opts.DAppAddress, opts.Hash, opts.Signature, "")
+	default:
+		return nil, fmt.Errorf("unsupported feed %s", feed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionID := uuid.New().String()
+
+	h.conn.lock.Lock()
+	h.conn.subscriptions[subscriptionID] = sub
+	h.conn.lock.Unlock()
+
+	go h.notify(subscriptionID, sub)
+
+	return subscriptionID, nil
+}
+
+func (h *wsHandler) unsubscribe(req *jsonrpc2.Request) (interface{}, error) {
+	var params []string
+	if req.Params != nil {
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	h.conn.lock.Lock()
+	defer h.conn.lock.Unlock()
+
+	for _, id := range params {
+		if sub, ok := h.conn.subscriptions[id]; ok {
+			h.gateway.unsubscribe(sub)
+			delete(h.conn.subscriptions, id)
+		}
+	}
+
+	return true, nil
+}
+
+// notify forwards the messages of the subscriber to the WS client until the subscription ends
+func (h *wsHandler) notify(subscriptionID string, sub *subscriber) {
+	for msg := range sub.ch {
+		var result interface{}
+
+		switch m := msg.(type) {
+		case *Intent:
+			result = intentNotification{
+				DAppAddress:   m.DAppAddress,
+				SenderAddress: m.SenderAddress,
+				IntentID:      m.ID,
+				Intent:        m.Payload,
+				Timestamp:     m.Timestamp.String(),
+			}
+		case *Solution:
+			result = newSolutionNotification(m)
+		}
+
+		err := h.conn.Notify(context.Background(), string(jsonrpc.RPCSubscribe), subscriptionNotification{
+			Subscription: subscriptionID,
+			Result:       result,
+		})
+		if err != nil {
+			return
+		}
+	}
+}
+
+func newSolutionNotification(solution *Solution) solutionNotification {
+	return solutionNotification{
+		IntentID:       solution.IntentID,
+		IntentSolution: solution.Payload,
+		SolutionID:     solution.ID,
+		SolverAddress:  solution.SolverAddress,
+	}
+}
+
+func unmarshalParams(req *jsonrpc2.Request, v interface{}) error {
+	if req.Params == nil {
+		return fmt.Errorf("params value is missing")
+	}
+
+	err := json.Unmarshal(*req.Params, v)
+	if err != nil {
+		return fmt.Errorf("failed to parse params: %v", err)
+	}
+
+	return nil
+}